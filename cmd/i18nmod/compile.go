@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml/repository"
+)
+
+func compileCmd(args []string) (err error) {
+	flags := flag.NewFlagSet("compile", flag.ContinueOnError)
+	localesDir := flags.String("dir", "", "YAML locales directory (required)")
+	options := &repository.CompilerOptions{}
+	flags.StringVar(&options.Output, "o", "", "output Go file (required)")
+	flags.StringVar(&options.Package, "pkg", "", "package name (default the output directory name)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: i18nmod compile -dir <locales dir> -o <file> [flags]\n\n"+
+			"Import the generated package and call Translator.LoadCompiled, or its Register function, to set its translations.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *localesDir == "" || options.Output == "" {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	backend := yaml.New()
+	if errs := backend.LoadDir(*localesDir); len(errs) > 0 {
+		return errs[0]
+	}
+	return repository.CompileTranslations(backend, options)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+)
+
+func coverageCmd(args []string) (err error) {
+	flags := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	localesDir := flags.String("dir", "", "YAML locales directory (required)")
+	reference := flags.String("ref", "en", "reference locale")
+	format := flags.String("format", "text", "output format: text or json")
+	verbose := flags.Bool("v", false, "list the keys of the text format")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: i18nmod coverage -dir <locales dir> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *localesDir == "" {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	backend := yaml.New()
+	if errs := backend.LoadDir(*localesDir); len(errs) > 0 {
+		return errs[0]
+	}
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err = tr.PreloadAll(); err != nil {
+		return
+	}
+
+	coverage := tr.Coverage(*reference)
+	switch *format {
+	case "json":
+		if coverage == nil {
+			coverage = []*i18nmod.Coverage{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(coverage)
+	case "text":
+		return i18nmod.WriteCoverageTable(os.Stdout, coverage, *verbose)
+	default:
+		return fmt.Errorf("invalid format %q", *format)
+	}
+}
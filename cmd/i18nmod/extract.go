@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/extract"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+func extractCmd(args []string) (err error) {
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	format := flags.String("format", "json", "output format: json or yaml")
+	output := flags.String("o", "", "output file (default stdout)")
+	localesDir := flags.String("dir", "", "YAML locales directory, to report the keys missing in it")
+	locales := flags.String("l", "", "comma separated locales checked by -dir (default all of the directory)")
+	stubs := flags.String("stubs", "", "write YAML stubs of the missing keys into this directory")
+	tests := flags.Bool("tests", false, "include the test files")
+	importPath := flags.String("importpath", "", "import path of the dir (default from go.mod or GOPATH)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: i18nmod extract [flags] [dir...]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	dirs := flags.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	keys, err := extractKeys(dirs, *tests, *importPath)
+	if err != nil {
+		return
+	}
+	if keys == nil {
+		keys = []*extract.Key{}
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		var f *os.File
+		if f, err = os.Create(*output); err != nil {
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(keys)
+	case "yaml":
+		var data []byte
+		if data, err = yaml2.Marshal(keys); err == nil {
+			_, err = w.Write(data)
+		}
+	default:
+		err = fmt.Errorf("invalid format %q", *format)
+	}
+	if err != nil || *localesDir == "" {
+		return
+	}
+
+	missing, err := missingKeys(keys, *localesDir, *locales)
+	if err != nil {
+		return
+	}
+	for _, m := range missing.Keys() {
+		fmt.Fprintf(os.Stderr, "%s: %s.%s missing in %q\n", m.Caller, m.Group, m.Key, m.Locale)
+	}
+	if *stubs != "" {
+		return missing.WriteYAMLStubs(*stubs)
+	}
+	return
+}
+
+// extractKeys returns the keys used by the packages of dirs, reporting the
+// unresolved ones. The importPath, if set, is the import path of the only dir.
+func extractKeys(dirs []string, tests bool, importPath string) (keys []*extract.Key, err error) {
+	if importPath != "" && len(dirs) > 1 {
+		return nil, fmt.Errorf("-importpath needs a single dir")
+	}
+	e := extract.New()
+	e.Tests = tests
+	if e.BaseDir, err = os.Getwd(); err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if importPath != "" {
+			err = e.WalkPackage(dir, importPath)
+		} else {
+			err = e.Walk(dir)
+		}
+		if err != nil {
+			return
+		}
+	}
+	for _, pos := range e.Unresolved {
+		fmt.Fprintf(os.Stderr, "%s: key not resolved\n", pos)
+	}
+	return e.Keys(), nil
+}
+
+// missingKeys returns the keys not found in the locales of the YAML directory.
+func missingKeys(keys []*extract.Key, dir, locales string) (*i18nmod.MissingCollector, error) {
+	backend := yaml.New()
+	if errs := backend.LoadDir(dir); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	var langs []string
+	if locales != "" {
+		langs = strings.Split(locales, ",")
+	} else {
+		langs = backend.ListLanguages()
+	}
+
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.Preload(langs); err != nil {
+		return nil, err
+	}
+
+	missing := i18nmod.NewMissingCollector()
+	for _, k := range keys {
+		for _, lang := range langs {
+			if t, _ := tr.Get(k.Group, k.Key, lang); t == nil {
+				missing.AddCaller(k.Group, k.Key, lang, k.Default, k.Locations[0])
+			}
+		}
+	}
+	return missing, nil
+}
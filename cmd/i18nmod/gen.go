@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml/repository"
+)
+
+func genCmd(args []string) (err error) {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	localesDir := flags.String("dir", "", "YAML locales directory (required)")
+	group := flags.String("group", "", "translations group (required)")
+	options := &repository.CompilerOptions{}
+	flags.StringVar(&options.Output, "o", "", "output Go file (default stdout)")
+	flags.StringVar(&options.Package, "pkg", "", "package name (default the output directory name)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: i18nmod gen -dir <locales dir> -group <group> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *localesDir == "" || *group == "" || (options.Output == "" && options.Package == "") {
+		if options.Output == "" && options.Package == "" {
+			fmt.Fprintln(flags.Output(), "-pkg is required without -o")
+		}
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	backend := yaml.New()
+	if errs := backend.LoadDir(*localesDir); len(errs) > 0 {
+		return errs[0]
+	}
+	if options.Output != "" {
+		return repository.CompileKeys(backend, *group, options)
+	}
+	src, err := repository.GenerateKeys(backend, *group, options)
+	if err != nil {
+		return
+	}
+	_, err = os.Stdout.Write(src)
+	return
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+)
+
+func lintCmd(args []string) (err error) {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	localesDir := flags.String("dir", "", "YAML locales directory (required)")
+	format := flags.String("format", "text", "output format: text or json")
+	tests := flags.Bool("tests", false, "include the test files of the source dirs")
+	importPath := flags.String("importpath", "", "import path of the source dir (default from go.mod or GOPATH)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: i18nmod lint -dir <locales dir> [flags] [source dir...]\n\n"+
+			"The source dirs give the data fields passed to the templates.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *localesDir == "" {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	backend := yaml.New()
+	if errs := backend.LoadDir(*localesDir); len(errs) > 0 {
+		return errs[0]
+	}
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+
+	linter := i18nmod.NewLinter(tr)
+	if flags.NArg() > 0 {
+		keys, err := extractKeys(flags.Args(), *tests, *importPath)
+		if err != nil {
+			return err
+		}
+		linter.Fields = map[string][]string{}
+		for _, k := range keys {
+			if !k.DynamicData {
+				linter.Fields[k.FullKey()] = k.Fields
+			}
+		}
+	}
+
+	issues, err := linter.Lint()
+	if err != nil {
+		return
+	}
+
+	switch *format {
+	case "json":
+		if issues == nil {
+			issues = []*i18nmod.LintIssue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(issues); err != nil {
+			return
+		}
+	case "text":
+		for _, issue := range issues {
+			fmt.Println(issue.Error())
+		}
+	default:
+		return fmt.Errorf("invalid format %q", *format)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%d issues found", len(issues))
+	}
+	return
+}
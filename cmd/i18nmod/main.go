@@ -0,0 +1,61 @@
+// Command i18nmod is the translations tool.
+//
+// Usage:
+//
+//	i18nmod compile -dir <locales dir> -o <file> [-pkg name]
+//	i18nmod coverage -dir <locales dir> [-ref locale] [flags]
+//	i18nmod extract [flags] [dir...]
+//	i18nmod gen -dir <locales dir> -group <group> [-o file] [-pkg name]
+//	i18nmod lint -dir <locales dir> [flags] [source dir...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]*command{
+	"compile":  {"compile the translations of a locales directory into Go code", compileCmd},
+	"coverage": {"compare the locales with a reference locale", coverageCmd},
+	"extract":  {"list the translation keys used by the Go source code", extractCmd},
+	"gen":      {"generate typed accessors of the keys of a group", genCmd},
+	"lint":     {"check the translations of a locales directory", lintCmd},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags] [args]\n\nCommands:\n", os.Args[0])
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,301 @@
+// Package composite is the backend of named layers of other backends, merged by
+// priority. For example: the embedded defaults, overridden by the customer
+// files, overridden by the database edits.
+//
+//	backend := composite.New(
+//		&composite.Layer{Name: "defaults", Backend: embedded},
+//		&composite.Layer{Name: "customer", Priority: 10, Backend: files},
+//		&composite.Layer{Name: "database", Priority: 20, Backend: db,
+//			Writable: true, Source: i18nsql.SourceName},
+//	)
+//	tr.AddBackend(backend)
+//
+// The writes are routed to the top writable layer.
+package composite
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+var _ i18nmod.Backend = &Backend{}
+
+// Layer is a named backend of the composite.
+type Layer struct {
+	Name string
+	// Priority orders the layers: the translations of the higher priority
+	// layers override the lower ones. The layers of the same priority are
+	// ordered by registration.
+	Priority int
+	Backend  i18nmod.Backend
+	// Writable enables routing the writes to this layer.
+	Writable bool
+	// Source returns the source of the translations of group and locale in
+	// the layer, used to write the translations the layer has not loaded yet.
+	// If nil, only the group and locales loaded from the layer are writable.
+	Source func(group, locale string) string
+}
+
+// Backend composite backend
+type Backend struct {
+	mu     sync.RWMutex
+	layers []*Layer
+	// providers are the layer names of the keys, by group and locale
+	providers map[string]map[string]map[string]string
+	// sources are the sources of the layers, by group and locale
+	sources map[string]map[string]map[string]*layerSources
+	// locales are the locales of the sources
+	locales map[string]string
+}
+
+// layerSources are the sources of the keys of a layer, by key, and the first
+// one by name, used to write the new keys.
+type layerSources struct {
+	first string
+	keys  map[string]string
+}
+
+// New new composite backend of layers
+func New(layers ...*Layer) *Backend {
+	backend := &Backend{
+		providers: map[string]map[string]map[string]string{},
+		sources:   map[string]map[string]map[string]*layerSources{},
+		locales:   map[string]string{},
+	}
+	backend.AddLayer(layers...)
+	return backend
+}
+
+// AddLayer adds the layers. It panics if a layer name is already used.
+func (backend *Backend) AddLayer(layers ...*Layer) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	for _, l := range layers {
+		for _, cur := range backend.layers {
+			if cur.Name == l.Name {
+				panic(fmt.Errorf("Duplicate layer %q", l.Name))
+			}
+		}
+		backend.layers = append(backend.layers, l)
+	}
+	sort.SliceStable(backend.layers, func(i, j int) bool {
+		return backend.layers[i].Priority < backend.layers[j].Priority
+	})
+}
+
+// Layers returns the layers, from the lowest priority to the highest.
+func (backend *Backend) Layers() []*Layer {
+	backend.mu.RLock()
+	defer backend.mu.RUnlock()
+	return append([]*Layer{}, backend.layers...)
+}
+
+// Layer returns the layer of name, or nil.
+func (backend *Backend) Layer(name string) *Layer {
+	for _, l := range backend.Layers() {
+		if l.Name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// TopWritable returns the writable layer of highest priority, or nil.
+func (backend *Backend) TopWritable() *Layer {
+	layers := backend.Layers()
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].Writable {
+			return layers[i]
+		}
+	}
+	return nil
+}
+
+func (backend *Backend) union(list func(b i18nmod.Backend) []string) (values []string) {
+	seen := map[string]bool{}
+	for _, l := range backend.Layers() {
+		for _, v := range list(l.Backend) {
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	return
+}
+
+func (backend *Backend) ListGroups() []string {
+	return backend.union(i18nmod.Backend.ListGroups)
+}
+
+func (backend *Backend) ListLanguages() []string {
+	return backend.union(i18nmod.Backend.ListLanguages)
+}
+
+// LoadTranslations merges the translations of the layers, from the lowest
+// priority to the highest, and records the layer of each key.
+func (backend *Backend) LoadTranslations(lang string, group string) (*i18nmod.Tree, error) {
+	var (
+		tree      = &i18nmod.Tree{}
+		providers = map[string]string{}
+		sources   = map[string]*layerSources{}
+	)
+	for _, l := range backend.Layers() {
+		t, err := l.Backend.LoadTranslations(lang, group)
+		if err != nil {
+			return nil, fmt.Errorf("Layer %q: %v", l.Name, err)
+		}
+		if t == nil {
+			continue
+		}
+		_ = t.WalkT(func(key string, tn *i18nmod.Translation) error {
+			providers[key] = l.Name
+			if tn.Source != nil {
+				ls, ok := sources[l.Name]
+				if !ok {
+					ls = &layerSources{first: *tn.Source, keys: map[string]string{}}
+					sources[l.Name] = ls
+				}
+				if *tn.Source < ls.first {
+					ls.first = *tn.Source
+				}
+				ls.keys[key] = *tn.Source
+			}
+			return nil
+		})
+		tree.Merge(t)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if backend.providers[group] == nil {
+		backend.providers[group] = map[string]map[string]string{}
+		backend.sources[group] = map[string]map[string]*layerSources{}
+	}
+	backend.providers[group][lang] = providers
+	backend.sources[group][lang] = sources
+	for _, ls := range sources {
+		for _, source := range ls.keys {
+			backend.locales[source] = lang
+		}
+	}
+	return tree, nil
+}
+
+// Provider returns the layer that provided key of group and locale, loading
+// them if not loaded yet. It returns nil if no layer has the key.
+func (backend *Backend) Provider(lang, group, key string) (*Layer, error) {
+	backend.mu.RLock()
+	providers, ok := backend.providers[group][lang]
+	backend.mu.RUnlock()
+	if !ok {
+		if _, err := backend.LoadTranslations(lang, group); err != nil {
+			return nil, err
+		}
+		backend.mu.RLock()
+		providers = backend.providers[group][lang]
+		backend.mu.RUnlock()
+	}
+	if name, ok := providers[key]; ok {
+		return backend.Layer(name), nil
+	}
+	return nil, nil
+}
+
+// route returns the top writable layer and a copy of t with the source of the
+// layer for lang: the source of the key, if the layer has it, or else the
+// first source of the layer or its Layer.Source.
+func (backend *Backend) route(lang string, t *i18nmod.Translation) (*Layer, *i18nmod.Translation, error) {
+	if t.Group == nil {
+		return nil, nil, fmt.Errorf("Translation %q has no group", t.Key)
+	}
+	l := backend.TopWritable()
+	if l == nil {
+		return nil, nil, errors.New("no writable layer")
+	}
+	group := *t.Group
+
+	var (
+		source string
+		ok     bool
+	)
+	backend.mu.RLock()
+	if ls := backend.sources[group][lang][l.Name]; ls != nil {
+		if source, ok = ls.keys[t.Key]; !ok {
+			source, ok = ls.first, true
+		}
+	}
+	backend.mu.RUnlock()
+	if !ok {
+		if l.Source == nil {
+			return nil, nil, fmt.Errorf("Layer %q has no translations of group %q and locale %q", l.Name, group, lang)
+		}
+		source = l.Source(group, lang)
+	}
+
+	routed := *t
+	routed.Source = &source
+	return l, &routed, nil
+}
+
+// locale returns the locale of the translation source.
+func (backend *Backend) locale(t *i18nmod.Translation) (string, error) {
+	if t.Source != nil {
+		backend.mu.RLock()
+		lang, ok := backend.locales[*t.Source]
+		backend.mu.RUnlock()
+		if ok {
+			return lang, nil
+		}
+	}
+	return "", fmt.Errorf("Translation %q has no loaded source", t.Key)
+}
+
+// SaveTranslation saves the translation to the top writable layer, in the
+// locale of its source.
+func (backend *Backend) SaveTranslation(t *i18nmod.Translation) error {
+	lang, err := backend.locale(t)
+	if err != nil {
+		return err
+	}
+	return backend.Save(lang, t)
+}
+
+// Save saves the translation of lang to the top writable layer.
+func (backend *Backend) Save(lang string, t *i18nmod.Translation) error {
+	l, routed, err := backend.route(lang, t)
+	if err != nil {
+		return err
+	}
+	if err = l.Backend.SaveTranslation(routed); err != nil {
+		return fmt.Errorf("Layer %q: %v", l.Name, err)
+	}
+	return nil
+}
+
+// DeleteTranslation deletes the translation from the top writable layer, in
+// the locale of its source. The translation of the lower layers, if any, is
+// used again.
+func (backend *Backend) DeleteTranslation(t *i18nmod.Translation) error {
+	lang, err := backend.locale(t)
+	if err != nil {
+		return err
+	}
+	return backend.Delete(lang, t)
+}
+
+// Delete deletes the translation of lang from the top writable layer.
+func (backend *Backend) Delete(lang string, t *i18nmod.Translation) error {
+	l, routed, err := backend.route(lang, t)
+	if err != nil {
+		return err
+	}
+	if err = l.Backend.DeleteTranslation(routed); err != nil {
+		return fmt.Errorf("Layer %q: %v", l.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package composite_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/composite"
+	i18nsql "github.com/moisespsena-go/i18n-modular/i18nmod/backends/sql"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	_ "modernc.org/sqlite"
+)
+
+func yamlBackend(content string) *yaml.Backend {
+	backend := yaml.New()
+	backend.AddInput("messages", "en", func() ([]byte, error) {
+		return []byte(content), nil
+	})
+	return backend
+}
+
+func TestBackend(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	database := i18nsql.New(db)
+	if err = database.CreateTables(); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := composite.New(
+		&composite.Layer{Name: "database", Priority: 20, Backend: database, Writable: true, Source: i18nsql.SourceName},
+		&composite.Layer{Name: "defaults", Backend: yamlBackend("hello: Hello\nbye: Bye\ntitle: Title\n")},
+	)
+	backend.AddLayer(&composite.Layer{Name: "customer", Priority: 10, Backend: yamlBackend("bye: Goodbye\n")})
+
+	var names []string
+	for _, l := range backend.Layers() {
+		names = append(names, l.Name)
+	}
+	if len(names) != 3 || names[0] != "defaults" || names[1] != "customer" || names[2] != "database" {
+		t.Errorf("invalid layers order %v", names)
+	}
+
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err = tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := tr.NewContext("en")
+	if got := ctx.T("messages.bye").Get(); got != "Goodbye" {
+		t.Errorf("expected %q, got %q", "Goodbye", got)
+	}
+
+	// the edits of the default translations are saved to the database
+	tn, _ := tr.Get("messages", "hello", "en")
+	tn.Value = "Hi"
+	if err = backend.SaveTranslation(tn); err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.Reload("en", "messages"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ctx.T("messages.hello").Get(); got != "Hi" {
+		t.Errorf("expected %q, got %q", "Hi", got)
+	}
+	for key, expected := range map[string]string{"hello": "database", "bye": "customer", "title": "defaults", "none": ""} {
+		l, err := backend.Provider("en", "messages", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var name string
+		if l != nil {
+			name = l.Name
+		}
+		if name != expected {
+			t.Errorf("%s: expected layer %q, got %q", key, expected, name)
+		}
+	}
+
+	// the deleted database edit reverts to the default translation
+	tn, _ = tr.Get("messages", "hello", "en")
+	if err = backend.DeleteTranslation(tn); err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.Reload("en", "messages"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ctx.T("messages.hello").Get(); got != "Hello" {
+		t.Errorf("expected %q, got %q", "Hello", got)
+	}
+
+	// new translations need the locale
+	group := "messages"
+	if err = backend.SaveTranslation(&i18nmod.Translation{Group: &group, Key: "new", Value: "New"}); err == nil {
+		t.Error("expected error of unknown locale")
+	}
+	if err = backend.Save("pt-BR", &i18nmod.Translation{Group: &group, Key: "new", Value: "Novo"}); err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := backend.Provider("pt-BR", "messages", "new"); l == nil || l.Name != "database" {
+		t.Errorf("expected database layer, got %v", l)
+	}
+}
+
+func TestSaveToKeySource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the writable layer has two files of the group and locale
+	files := yaml.New()
+	for name, content := range map[string]string{"a": "hello: Hello\n", "b": "bye: Bye\n"} {
+		pth := filepath.Join(dir, name, "messages", "en.yaml")
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a", "b"} {
+		if errs := files.LoadDir(filepath.Join(dir, name)); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+	}
+
+	backend := composite.New(
+		&composite.Layer{Name: "defaults", Backend: yamlBackend("hello: Hello\nbye: Bye\n")},
+		&composite.Layer{Name: "files", Priority: 10, Backend: files, Writable: true},
+	)
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err = tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	tn, _ := tr.Get("messages", "bye", "en")
+	tn.Value = "Goodbye"
+	if err = backend.SaveTranslation(tn); err != nil {
+		t.Fatal(err)
+	}
+	group := "messages"
+	if err = backend.Save("en", &i18nmod.Translation{Group: &group, Key: "new", Value: "New"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string][]string{
+		"a": {"hello: Hello", "new: New"},
+		"b": {"bye: Goodbye"},
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name, "messages", "en.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(data)); got != strings.Join(expected, "\n") {
+			t.Errorf("%s: expected %q, got %q", name, strings.Join(expected, "\n"), got)
+		}
+	}
+}
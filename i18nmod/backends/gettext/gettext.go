@@ -0,0 +1,263 @@
+// Package gettext is the backend of the translations in gettext PO and MO
+// files.
+//
+// The files are loaded as the YAML ones: LoadDir reads the group from the
+// directory and the locale from the file name ("messages/pt_BR.po"). The files
+// of the LoadDir root are catalogs of all groups. The Scheme maps each entry to
+// its group and key, by default from the msgctxt (see ContextScheme).
+//
+// The msgid_plural entries are plurals: their msgstr forms are set to the CLDR
+// categories of the locale using the Plural-Forms header. The msgstr values
+// with "{{" are templates.
+package gettext
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	i18nyaml "github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"gopkg.in/fatih/set.v0"
+)
+
+var _ i18nmod.Backend = &Backend{}
+
+type Input = i18nyaml.Input
+
+// Scheme returns the group and the key of the entry of an input of group. The
+// group of the catalog inputs is empty. The entries of empty group or key, or
+// of relative key (starting with "." or "/"), are skipped.
+type Scheme func(group string, e *Entry) (entryGroup, key string)
+
+// ContextScheme uses the msgctxt as key, or the msgid if the entry has no
+// context. In catalogs, the key is "group.key", like "admin:users.title".
+func ContextScheme(group string, e *Entry) (string, string) {
+	key := e.Context
+	if key == "" {
+		key = e.ID
+	}
+	if group == "" {
+		if i := strings.IndexByte(key, '.'); i > 0 {
+			return key[0:i], key[i+1:]
+		}
+	}
+	return group, key
+}
+
+// IDScheme uses the msgid as key, the source text, prefixed by the msgctxt and
+// "." if any. In catalogs, the group is the msgctxt.
+func IDScheme(group string, e *Entry) (string, string) {
+	if group == "" {
+		return e.Context, e.ID
+	}
+	if e.Context != "" {
+		return group, e.Context + "." + e.ID
+	}
+	return group, e.ID
+}
+
+// New new gettext backend for I18n
+func New() *Backend {
+	return &Backend{inputs: make(map[string]map[string][]*Input), Scheme: ContextScheme}
+}
+
+// Backend gettext backend
+type Backend struct {
+	inputs map[string]map[string][]*Input
+	// Scheme maps the entries to the group keys.
+	Scheme Scheme
+	// Fuzzy loads the entries with the "fuzzy" flag, skipped by default.
+	Fuzzy bool
+}
+
+// LoadContent returns the trees of the PO or MO content, by group. The input of
+// group is empty for catalogs.
+func (backend *Backend) LoadContent(name *string, lang, group string, content []byte) (map[string]*i18nmod.Tree, error) {
+	c, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	pf, err := c.PluralForms()
+	if err != nil {
+		return nil, err
+	}
+
+	trees := map[string]*i18nmod.Tree{}
+	for _, e := range c.Entries {
+		if !e.Translated() || (e.Fuzzy() && !backend.Fuzzy) {
+			continue
+		}
+		g, key := backend.Scheme(group, e)
+		if g == "" || key == "" || key[0] == '.' || key[0] == '/' {
+			continue
+		}
+
+		t := &i18nmod.Translation{Key: key, Source: name}
+		if e.IDPlural == "" {
+			t.Value = e.Str[0]
+			if strings.Contains(t.Value, "{{") {
+				if t.Template, err = i18nmod.ParseLazyTemplate(t.Value); err != nil {
+					return nil, fmt.Errorf("Parse translation [%v.%v] template failed: %v", g, key, err)
+				}
+			}
+		} else {
+			forms := make([]interface{}, len(e.Str))
+			for i, s := range e.Str {
+				forms[i] = s
+				if strings.Contains(s, "{{") {
+					if forms[i], err = i18nmod.ParseLazyTemplate(s); err != nil {
+						return nil, fmt.Errorf("Parse translation [%v.%v][%d] template failed: %v", g, key, i, err)
+					}
+				}
+			}
+			t.Plural = pf.Plural(lang, forms)
+		}
+
+		if trees[g] == nil {
+			trees[g] = &i18nmod.Tree{}
+		}
+		trees[g].Add(t)
+	}
+	return trees, nil
+}
+
+func (backend *Backend) LoadTranslations(language string, group string) (*i18nmod.Tree, error) {
+	tree := &i18nmod.Tree{}
+	if group == "" {
+		return tree, nil
+	}
+
+	for _, g := range []string{group, ""} {
+		for _, input := range backend.inputs[g][language] {
+			content, err := input.Reader()
+			if err != nil {
+				return nil, fmt.Errorf("Load group '%v' of input '%v' failed: %v", group, input.Name, err)
+			}
+			trees, err := backend.LoadContent(input.Source(), language, g, content)
+			if err != nil {
+				return nil, fmt.Errorf("Load group '%v' of input '%v' failed: %v", group, input.Name, err)
+			}
+			if t := trees[group]; t != nil {
+				tree.Merge(t)
+			}
+		}
+	}
+
+	return tree, nil
+}
+
+// SaveTranslation save translation into gettext backend, not implemented
+func (backend *Backend) SaveTranslation(t *i18nmod.Translation) error {
+	return errors.New("not implemented")
+}
+
+// DeleteTranslation delete translation into gettext backend, not implemented
+func (backend *Backend) DeleteTranslation(t *i18nmod.Translation) error {
+	return errors.New("not implemented")
+}
+
+func (backend *Backend) GetFiles() map[string]map[string][]*Input {
+	return backend.inputs
+}
+
+// ListGroups returns the groups of the inputs and of the catalog entries. The
+// catalogs that fail to load are reported by LoadTranslations.
+func (backend *Backend) ListGroups() []string {
+	st := set.New(set.NonThreadSafe)
+	for group, langs := range backend.inputs {
+		if group != "" {
+			st.Add(group)
+			continue
+		}
+		for lang, inputs := range langs {
+			for _, input := range inputs {
+				content, err := input.Reader()
+				if err != nil {
+					continue
+				}
+				trees, _ := backend.LoadContent(input.Source(), lang, group, content)
+				for g := range trees {
+					st.Add(g)
+				}
+			}
+		}
+	}
+
+	var groups []string
+	st.Each(func(item interface{}) bool {
+		groups = append(groups, item.(string))
+		return true
+	})
+	return groups
+}
+
+func (backend *Backend) ListLanguages() (langs []string) {
+	st := set.New(set.NonThreadSafe)
+	for group := range backend.inputs {
+		for lang := range backend.inputs[group] {
+			st.Add(lang)
+		}
+	}
+
+	st.Each(func(item interface{}) bool {
+		langs = append(langs, item.(string))
+		return true
+	})
+
+	return langs
+}
+
+// LoadDir adds the ".po" and ".mo" files of path.
+func (backend *Backend) LoadDir(path string) (errs []error) {
+	err := i18nmod.WalkDirExt("", path, func(group string, items []string) error {
+		group = i18nmod.FormatGroupName(group)
+		for _, item := range items {
+			item := item
+			err := backend.addInput("file", group, fileLang(item), item, func() ([]byte, error) {
+				return ioutil.ReadFile(item)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, ".po", ".mo")
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+func fileLang(name string) string {
+	name = filepath.Base(name)
+	return strings.Replace(strings.TrimSuffix(name, filepath.Ext(name)), "_", "-", -1)
+}
+
+// AddInput adds the PO or MO content of group. The empty group adds a catalog.
+func (backend *Backend) AddInput(group, lang string, reader func() ([]byte, error)) (err error) {
+	return backend.addInput("raw", group, lang, "", reader)
+}
+
+func (backend *Backend) addInput(typ, group, lang, pth string, reader func() ([]byte, error)) (err error) {
+	if lang, err = i18nmod.FormatLang(lang); err != nil {
+		return
+	}
+
+	if _, ok := backend.inputs[group]; !ok {
+		backend.inputs[group] = make(map[string][]*Input)
+	}
+
+	if typ != "" {
+		typ = "+" + typ
+	}
+
+	backend.inputs[group][lang] = append(backend.inputs[group][lang], &Input{
+		Name:   "gettext" + typ + "://" + group + "[" + lang + "]",
+		Reader: reader,
+		Path:   pth,
+	})
+	return nil
+}
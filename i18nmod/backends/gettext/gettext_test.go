@@ -0,0 +1,183 @@
+package gettext_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/gettext"
+)
+
+func TestLoadDir(t *testing.T) {
+	backend := gettext.New()
+	if errs := backend.LoadDir("tests"); errs != nil {
+		t.Fatal(errs)
+	}
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	pt := tr.NewContext("pt-BR")
+	for key, expected := range map[string]string{
+		"messages.hello":        "Olá",
+		"messages.Multi line":   "Linha 1\nLinha 2",
+		"messages.draft":        "messages.draft",
+		"messages.untranslated": "messages.untranslated",
+	} {
+		if got := pt.T(key).Get(); got != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, got)
+		}
+	}
+	if got := pt.T("messages.user.greet").Data(map[string]string{"Name": "Ana"}).Get(); got != "Oi Ana" {
+		t.Errorf("expected %q, got %q", "Oi Ana", got)
+	}
+	for n, expected := range map[int]string{0: "0 item", 1: "1 item", 2: "2 itens"} {
+		if got := pt.T("messages.items").Count(n).Get(); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+
+	ru := tr.NewContext("ru")
+	if got := ru.T("admin:users.title").Get(); got != "Пользователи" {
+		t.Errorf("expected %q, got %q", "Пользователи", got)
+	}
+	for n, expected := range map[int]string{1: "1 файл", 3: "3 файла", 5: "5 файлов", 11: "11 файлов", 21: "21 файл", 22: "22 файла"} {
+		if got := ru.T("messages.files").Count(n).Get(); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	pf, err := gettext.ParsePluralForms("nplurals=3; plural=n==1 ? 0 : n==2 ? 1 : 2;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, expected := range map[int64]int{0: 2, 1: 0, 2: 1, 3: 2} {
+		if got := pf.Index(n); got != expected {
+			t.Errorf("%d: expected %d, got %d", n, expected, got)
+		}
+	}
+
+	// the forms of "en" are "one" and "other", so the form of 2 is an exact
+	// case
+	plural := pf.Plural("en", []interface{}{"one", "two", "other"})
+	for n, expected := range map[int]string{1: "one", 2: "two", 3: "other", 100: "other"} {
+		if got := plural.MustFind(n); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+
+	for _, header := range []string{"", "nplurals=2;", "nplurals=2; plural=(n !=;", "nplurals=x; plural=n"} {
+		if _, err := gettext.ParsePluralForms(header); err == nil {
+			t.Errorf("%q: expected error", header)
+		}
+	}
+}
+
+// mo returns the MO content of the original and translation strings.
+func mo(pairs ...string) []byte {
+	n := len(pairs) / 2
+	var b bytes.Buffer
+	offset := 28 + n*16
+	header := []uint32{0x950412de, 0, uint32(n), 28, uint32(28 + n*8), 0, 0}
+	var tables [2][]uint32
+	var data bytes.Buffer
+	for j := 0; j < 2; j++ {
+		for i := 0; i < n; i++ {
+			s := pairs[i*2+j]
+			tables[j] = append(tables[j], uint32(len(s)), uint32(offset+data.Len()))
+			data.WriteString(s + "\x00")
+		}
+	}
+	binary.Write(&b, binary.LittleEndian, header)
+	binary.Write(&b, binary.LittleEndian, tables[0])
+	binary.Write(&b, binary.LittleEndian, tables[1])
+	b.Write(data.Bytes())
+	return b.Bytes()
+}
+
+func TestMO(t *testing.T) {
+	backend := gettext.New()
+	backend.AddInput("messages", "fr", func() ([]byte, error) {
+		return mo(
+			"", "Plural-Forms: nplurals=2; plural=(n > 1);\n",
+			"hello\x04Hello", "Bonjour",
+			"files\x04file\x00files", "fichier\x00fichiers",
+		), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tr.NewContext("fr")
+	if got := ctx.T("messages.hello").Get(); got != "Bonjour" {
+		t.Errorf("expected %q, got %q", "Bonjour", got)
+	}
+	for n, expected := range map[int]string{0: "fichier", 1: "fichier", 2: "fichiers"} {
+		if got := ctx.T("messages.files").Count(n).Get(); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+}
+
+func TestWritePOT(t *testing.T) {
+	backend := gettext.New()
+	backend.LoadDir("tests")
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := gettext.WritePOT(&b, tr, "pt-BR", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"msgctxt \"messages.hello\"\nmsgid \"Olá\"\nmsgstr \"\"\n",
+		"#: tests/messages/pt_BR.po\nmsgctxt \"messages.items\"\nmsgid \"{{count}} item\"\nmsgid_plural \"{{count}} itens\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n",
+		"msgctxt \"messages.Multi line\"\nmsgid \"\"\n\"Linha 1\\n\"\n\"Linha 2\"\n",
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("expected:\n%s\nin:\n%s", expected, b.String())
+		}
+	}
+
+	c, err := gettext.ParsePO(b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Entries) != 4 {
+		t.Errorf("expected 4 entries, got %d", len(c.Entries))
+	}
+	for _, e := range c.Entries {
+		if group, _ := gettext.ContextScheme("", e); group != "messages" {
+			t.Errorf("%q: expected group %q, got %q", e.Context, "messages", group)
+		}
+	}
+
+	// the ordinal only translations are not written
+	tr.NewGroup("pt-BR", "ranking", func(tree *i18nmod.Tree) {
+		tree.Add(&i18nmod.Translation{Key: "title", Value: "Classificação"})
+		tree.Add(&i18nmod.Translation{Key: "place", Ordinal: &i18nmod.Plural{Ordinal: true, Cases: map[interface{}]interface{}{
+			"other": i18nmod.NewLazyTemplate("{{count}}º lugar"),
+		}}})
+	})
+	b.Reset()
+	if err = gettext.WritePOT(&b, tr, "pt-BR", "ranking"); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = gettext.ParsePO(b.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Entries) != 1 || c.Entries[0].Context != "title" {
+		t.Errorf("expected the title entry, got %v", c.Entries)
+	}
+}
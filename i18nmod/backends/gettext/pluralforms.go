@@ -0,0 +1,285 @@
+package gettext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+// DefaultPluralForms is used by the catalogs without the Plural-Forms header.
+const DefaultPluralForms = "nplurals=2; plural=(n != 1);"
+
+// PluralForms is the parsed Plural-Forms header, like
+// "nplurals=2; plural=(n != 1);".
+type PluralForms struct {
+	N    int
+	Expr string
+	eval func(n int64) int64
+}
+
+// ParsePluralForms parses the Plural-Forms header value. The plural expression
+// is a C expression of n.
+func ParsePluralForms(header string) (*PluralForms, error) {
+	pf := &PluralForms{}
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("Invalid Plural-Forms %q", header)
+		}
+		switch name, value := strings.TrimSpace(part[0:eq]), strings.TrimSpace(part[eq+1:]); name {
+		case "nplurals":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("Invalid Plural-Forms nplurals %q", value)
+			}
+			pf.N = n
+		case "plural":
+			p := &exprParser{s: value}
+			eval, err := p.parse()
+			if err != nil {
+				return nil, fmt.Errorf("Invalid Plural-Forms plural %q: %v", value, err)
+			}
+			pf.Expr, pf.eval = value, eval
+		}
+	}
+	if pf.N == 0 || pf.eval == nil {
+		return nil, fmt.Errorf("Invalid Plural-Forms %q", header)
+	}
+	return pf, nil
+}
+
+// Index returns the msgstr index of n.
+func (pf *PluralForms) Index(n int64) int {
+	i := pf.eval(n)
+	if i < 0 || i >= int64(pf.N) {
+		return pf.N - 1
+	}
+	return int(i)
+}
+
+// maxExactCase is the last count checked by Plural.
+const maxExactCase = 200
+
+// Plural returns the plural of the msgstr forms. Each form is set to the CLDR
+// category of locale of its first count, and the counts up to 200 whose form is
+// not the form of their category are set as exact cases.
+func (pf *PluralForms) Plural(locale string, forms []interface{}) *i18nmod.Plural {
+	plural := &i18nmod.Plural{Locale: locale}
+	categories := map[string]int{}
+	var exact []int64
+
+	for n := int64(0); n <= maxExactCase; n++ {
+		i := pf.Index(n)
+		if i >= len(forms) {
+			continue
+		}
+		category := string(plural.Category(n))
+		if ci, ok := categories[category]; !ok {
+			categories[category] = i
+		} else if ci != i {
+			exact = append(exact, n)
+		}
+	}
+	if _, ok := categories["other"]; !ok && len(forms) > 0 {
+		categories["other"] = len(forms) - 1
+	}
+
+	for category, i := range categories {
+		plural.AddCase(category, forms[i])
+	}
+	for _, n := range exact {
+		plural.AddCase(int(n), forms[pf.Index(n)])
+	}
+	return plural
+}
+
+// exprParser parses the C expressions of the plural header: the ternary,
+// logical, comparison and arithmetic operators on n and integers.
+type exprParser struct {
+	s   string
+	pos int
+}
+
+type evalFunc = func(n int64) int64
+
+func (p *exprParser) parse() (evalFunc, error) {
+	f, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip(); p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at %d", p.s[p.pos:], p.pos)
+	}
+	return f, nil
+}
+
+func (p *exprParser) skip() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes op if it is the next token.
+func (p *exprParser) accept(op string) bool {
+	p.skip()
+	if strings.HasPrefix(p.s[p.pos:], op) {
+		// "<" and ">" are not "<=" and ">=", "!" is not "!="
+		if len(op) == 1 && strings.ContainsRune("<>!=", rune(op[0])) && strings.HasPrefix(p.s[p.pos+1:], "=") {
+			return false
+		}
+		p.pos += len(op)
+		return true
+	}
+	return false
+}
+
+func bool64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *exprParser) ternary() (evalFunc, error) {
+	cond, err := p.binary(0)
+	if err != nil || !p.accept("?") {
+		return cond, err
+	}
+	a, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(":") {
+		return nil, fmt.Errorf("expected ':' at %d", p.pos)
+	}
+	b, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int64) int64 {
+		if cond(n) != 0 {
+			return a(n)
+		}
+		return b(n)
+	}, nil
+}
+
+// binaryOps are the binary operators by precedence, from the lowest.
+var binaryOps = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) binary(level int) (evalFunc, error) {
+	if level == len(binaryOps) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op string
+		for _, o := range binaryOps[level] {
+			if p.accept(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryFunc(op, left, right)
+	}
+}
+
+func binaryFunc(op string, a, b evalFunc) evalFunc {
+	switch op {
+	case "||":
+		return func(n int64) int64 { return bool64(a(n) != 0 || b(n) != 0) }
+	case "&&":
+		return func(n int64) int64 { return bool64(a(n) != 0 && b(n) != 0) }
+	case "==":
+		return func(n int64) int64 { return bool64(a(n) == b(n)) }
+	case "!=":
+		return func(n int64) int64 { return bool64(a(n) != b(n)) }
+	case "<=":
+		return func(n int64) int64 { return bool64(a(n) <= b(n)) }
+	case ">=":
+		return func(n int64) int64 { return bool64(a(n) >= b(n)) }
+	case "<":
+		return func(n int64) int64 { return bool64(a(n) < b(n)) }
+	case ">":
+		return func(n int64) int64 { return bool64(a(n) > b(n)) }
+	case "+":
+		return func(n int64) int64 { return a(n) + b(n) }
+	case "-":
+		return func(n int64) int64 { return a(n) - b(n) }
+	case "*":
+		return func(n int64) int64 { return a(n) * b(n) }
+	case "/":
+		return func(n int64) int64 {
+			if d := b(n); d != 0 {
+				return a(n) / d
+			}
+			return 0
+		}
+	default:
+		return func(n int64) int64 {
+			if d := b(n); d != 0 {
+				return a(n) % d
+			}
+			return 0
+		}
+	}
+}
+
+func (p *exprParser) unary() (evalFunc, error) {
+	if p.accept("!") {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int64) int64 { return bool64(f(n) == 0) }, nil
+	}
+	if p.accept("(") {
+		f, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ')' at %d", p.pos)
+		}
+		return f, nil
+	}
+	if p.accept("n") {
+		return func(n int64) int64 { return n }, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("unexpected %q at %d", p.s[p.pos:], p.pos)
+	}
+	v, err := strconv.ParseInt(p.s[start:p.pos], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return func(int64) int64 { return v }, nil
+}
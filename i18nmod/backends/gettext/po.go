@@ -0,0 +1,250 @@
+package gettext
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Entry is a message of a catalog.
+type Entry struct {
+	Context  string
+	ID       string
+	IDPlural string
+	// Str are the msgstr forms. The singular messages have one form.
+	Str        []string
+	References []string
+	Flags      []string
+}
+
+// Fuzzy reports whether the entry has the "fuzzy" flag.
+func (e *Entry) Fuzzy() bool {
+	for _, f := range e.Flags {
+		if f == "fuzzy" {
+			return true
+		}
+	}
+	return false
+}
+
+// Translated reports whether the entry has a non empty msgstr form.
+func (e *Entry) Translated() bool {
+	for _, s := range e.Str {
+		if s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Catalog is a parsed PO or MO file.
+type Catalog struct {
+	// Header are the fields of the header entry, the msgstr of the empty
+	// msgid.
+	Header  map[string]string
+	Entries []*Entry
+}
+
+// PluralForms returns the parsed Plural-Forms header, or DefaultPluralForms.
+func (c *Catalog) PluralForms() (*PluralForms, error) {
+	if h, ok := c.Header["Plural-Forms"]; ok {
+		return ParsePluralForms(h)
+	}
+	return ParsePluralForms(DefaultPluralForms)
+}
+
+func (c *Catalog) add(e *Entry) {
+	if e.ID == "" && e.Context == "" {
+		for _, line := range strings.Split(strings.Join(e.Str, ""), "\n") {
+			if i := strings.IndexByte(line, ':'); i > 0 {
+				c.Header[strings.TrimSpace(line[0:i])] = strings.TrimSpace(line[i+1:])
+			}
+		}
+		return
+	}
+	c.Entries = append(c.Entries, e)
+}
+
+// Parse parses the MO content, found by its magic number, or the PO content.
+func Parse(content []byte) (*Catalog, error) {
+	if len(content) >= 4 {
+		if magic := binary.LittleEndian.Uint32(content); magic == moMagic || magic == moMagicSwapped {
+			return ParseMO(content)
+		}
+	}
+	return ParsePO(content)
+}
+
+// ParsePO parses the PO content. The obsolete entries ("#~") are ignored.
+func ParsePO(content []byte) (*Catalog, error) {
+	c := &Catalog{Header: map[string]string{}}
+	var (
+		e       = &Entry{}
+		started bool
+		// target is the field of the last keyword, that receives the
+		// continuation strings
+		target *string
+	)
+	flush := func() {
+		if started {
+			c.add(e)
+		}
+		e, started, target = &Entry{}, false, nil
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(content))
+	s.Buffer(make([]byte, 64*1024), len(content)+1)
+	for lineNo := 1; s.Scan(); lineNo++ {
+		line := strings.TrimSpace(s.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#"):
+			if started && target != nil && e.Str != nil {
+				flush()
+			}
+			switch {
+			case strings.HasPrefix(line, "#,"):
+				for _, f := range strings.Split(line[2:], ",") {
+					if f = strings.TrimSpace(f); f != "" {
+						e.Flags = append(e.Flags, f)
+					}
+				}
+			case strings.HasPrefix(line, "#:"):
+				e.References = append(e.References, strings.Fields(line[2:])...)
+			}
+			continue
+		case line[0] == '"':
+			if target == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", lineNo)
+			}
+			v, err := unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			*target += v
+			continue
+		}
+
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: invalid line %q", lineNo, line)
+		}
+		keyword := line[0:i]
+		v, err := unquote(strings.TrimSpace(line[i:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			if started {
+				flush()
+			}
+			e.Context, target = v, &e.Context
+		case keyword == "msgid":
+			if started && (e.ID != "" || e.Str != nil) {
+				flush()
+			}
+			e.ID, target = v, &e.ID
+		case keyword == "msgid_plural":
+			e.IDPlural, target = v, &e.IDPlural
+		case keyword == "msgstr":
+			e.Str = append(e.Str, v)
+			target = &e.Str[len(e.Str)-1]
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			n, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+			if err != nil || n != len(e.Str) {
+				return nil, fmt.Errorf("line %d: invalid index of %q", lineNo, keyword)
+			}
+			e.Str = append(e.Str, v)
+			target = &e.Str[n]
+		default:
+			return nil, fmt.Errorf("line %d: invalid keyword %q", lineNo, keyword)
+		}
+		started = true
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return c, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s: %v", s, err)
+	}
+	return v, nil
+}
+
+const (
+	moMagic        = 0x950412de
+	moMagicSwapped = 0xde120495
+)
+
+// ParseMO parses the compiled MO content.
+func ParseMO(content []byte) (*Catalog, error) {
+	if len(content) < 28 {
+		return nil, fmt.Errorf("invalid MO content")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if order.Uint32(content) == moMagicSwapped {
+		order = binary.BigEndian
+	}
+	if order.Uint32(content) != moMagic {
+		return nil, fmt.Errorf("invalid MO magic number")
+	}
+
+	var (
+		count     = order.Uint32(content[8:])
+		originals = order.Uint32(content[12:])
+		strs      = order.Uint32(content[16:])
+	)
+	str := func(table uint32, i uint32) (string, error) {
+		pos := uint64(table) + uint64(i)*8
+		if pos+8 > uint64(len(content)) {
+			return "", fmt.Errorf("invalid MO string table")
+		}
+		length, offset := uint64(order.Uint32(content[pos:])), uint64(order.Uint32(content[pos+4:]))
+		if offset+length > uint64(len(content)) {
+			return "", fmt.Errorf("invalid MO string %d", i)
+		}
+		return string(content[offset : offset+length]), nil
+	}
+
+	c := &Catalog{Header: map[string]string{}}
+	for i := uint32(0); i < count; i++ {
+		id, err := str(originals, i)
+		if err != nil {
+			return nil, err
+		}
+		s, err := str(strs, i)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &Entry{Str: strings.Split(s, "\x00")}
+		if j := strings.IndexByte(id, '\x04'); j >= 0 {
+			e.Context, id = id[0:j], id[j+1:]
+		}
+		if j := strings.IndexByte(id, '\x00'); j >= 0 {
+			e.ID, e.IDPlural = id[0:j], id[j+1:]
+		} else {
+			e.ID = id
+		}
+		c.add(e)
+	}
+	return c, nil
+}
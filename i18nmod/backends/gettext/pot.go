@@ -0,0 +1,135 @@
+package gettext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+// potHeader is the header entry of the templates, with the placeholders of
+// xgettext.
+const potHeader = `msgid ""
+msgstr ""
+"Project-Id-Version: PACKAGE VERSION\n"
+"Language: \n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=INTEGER; plural=EXPRESSION;\n"
+`
+
+// WritePOT writes the PO template of the loaded translations of the reference
+// locale. If group is empty, it writes the catalog of all groups, with the
+// "group.key" contexts, else the group template with the key contexts. Both
+// are read back by ContextScheme. The aliases are not written, and neither are
+// the translations of only ordinal cases, because gettext has no ordinal
+// plural forms.
+func WritePOT(w io.Writer, tr *i18nmod.Translator, reference, group string) error {
+	type entry struct {
+		group, key string
+		t          *i18nmod.Translation
+	}
+	var entries []entry
+
+	tr.RLock()
+	for g, locales := range tr.Groups {
+		if group != "" && g != group {
+			continue
+		}
+		for key, t := range locales[reference] {
+			if t.Alias == "" && !ordinalOnly(t) {
+				entries = append(entries, entry{g, key, t})
+			}
+		}
+	}
+	tr.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].group != entries[j].group {
+			return entries[i].group < entries[j].group
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(potHeader)
+	for _, e := range entries {
+		ctx := e.key
+		if group == "" {
+			ctx = e.group + "." + e.key
+		}
+		id, plural := sourceText(e.t)
+
+		bw.WriteString("\n")
+		if e.t.Source != nil {
+			fmt.Fprintf(bw, "#: %s\n", *e.t.Source)
+		}
+		writeString(bw, "msgctxt", ctx)
+		writeString(bw, "msgid", id)
+		if e.t.Plural != nil {
+			writeString(bw, "msgid_plural", plural)
+			bw.WriteString("msgstr[0] \"\"\nmsgstr[1] \"\"\n")
+		} else {
+			bw.WriteString("msgstr \"\"\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// sourceText returns the msgid and msgid_plural texts of t. The plural ones are
+// the "one" and "other" cases.
+func sourceText(t *i18nmod.Translation) (id, plural string) {
+	switch {
+	case t.Plural != nil:
+		return caseText(t.Plural.Cases["one"]), caseText(t.Plural.Cases["other"])
+	case t.Message != nil:
+		return t.Message.Pattern, ""
+	case t.Template != nil:
+		return t.Template.Text, ""
+	case t.Select != nil:
+		return caseText(t.Select.Cases["other"]), ""
+	}
+	return t.Value, ""
+}
+
+// ordinalOnly returns whether t has the ordinal cases only.
+func ordinalOnly(t *i18nmod.Translation) bool {
+	return t.Ordinal != nil && t.Plural == nil && t.Message == nil && t.Template == nil &&
+		t.Select == nil && t.Value == ""
+}
+
+func caseText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *i18nmod.LazyTemplate:
+		return v.Text
+	case *i18nmod.Plural:
+		return caseText(v.Cases["other"])
+	case *i18nmod.Select:
+		return caseText(v.Cases["other"])
+	}
+	return fmt.Sprint(value)
+}
+
+// writeString writes the keyword and its quoted value. The multiline values
+// are split after each "\n".
+func writeString(w *bufio.Writer, keyword, value string) {
+	lines := strings.SplitAfter(value, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[0 : len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		fmt.Fprintf(w, "%s %s\n", keyword, strconv.Quote(value))
+		return
+	}
+	fmt.Fprintf(w, "%s \"\"\n", keyword)
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s\n", strconv.Quote(line))
+	}
+}
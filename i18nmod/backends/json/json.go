@@ -0,0 +1,232 @@
+// Package json is the backend of the translations in nested JSON files, like
+// the i18next ones. The keys use the suffixes of the YAML backend: "~"
+// template, "@" alias, "&" link, "*" plural, "#" ordinal, "?" select and "!"
+// ICU message.
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	i18nyaml "github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"gopkg.in/fatih/set.v0"
+	"gopkg.in/yaml.v2"
+)
+
+var _ i18nmod.Backend = &Backend{}
+
+type Input = i18nyaml.Input
+
+// New new JSON backend for I18n
+func New() *Backend {
+	return &Backend{inputs: make(map[string]map[string][]*Input)}
+}
+
+// Backend JSON backend
+type Backend struct {
+	inputs map[string]map[string][]*Input
+}
+
+// Decode decodes the JSON content keeping the order of the object keys, as the
+// YAML backend reads its files. The integer keys of the plural and ordinal
+// cases, like "0", are decoded as int.
+func Decode(content []byte) (slice yaml.MapSlice, err error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+
+	var value interface{}
+	if value, err = decodeValue(dec, false); err != nil {
+		return
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid content after the top-level object")
+	}
+	var ok bool
+	if slice, ok = value.(yaml.MapSlice); !ok {
+		return nil, errors.New("the top-level value is not an object")
+	}
+	return slice, nil
+}
+
+// decodeValue decodes the next value. cases is true for the objects of plural
+// and ordinal cases.
+func decodeValue(dec *json.Decoder, cases bool) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			slice := yaml.MapSlice{}
+			for dec.More() {
+				if tok, err = dec.Token(); err != nil {
+					return nil, err
+				}
+				name := tok.(string)
+				var key interface{} = name
+				if i, err := strconv.Atoi(name); err == nil && cases && strconv.Itoa(i) == name {
+					key = i
+				}
+				value, err := decodeValue(dec, strings.HasSuffix(name, "*") || strings.HasSuffix(name, "#"))
+				if err != nil {
+					return nil, err
+				}
+				slice = append(slice, yaml.MapItem{Key: key, Value: value})
+			}
+			_, err = dec.Token()
+			return slice, err
+		case '[':
+			items := []interface{}{}
+			for dec.More() {
+				value, err := decodeValue(dec, false)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, value)
+			}
+			_, err = dec.Token()
+			return items, err
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i), nil
+		}
+		return v.Float64()
+	}
+	return tok, nil
+}
+
+// LoadContent load JSON content
+func (backend *Backend) LoadContent(name *string, content []byte) (*i18nmod.Tree, error) {
+	slice, err := Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	return i18nyaml.Import(name, slice)
+}
+
+func (backend *Backend) LoadTranslations(language string, group string) (*i18nmod.Tree, error) {
+	tree := &i18nmod.Tree{}
+
+	for _, input := range backend.inputs[group][language] {
+		content, err := input.Reader()
+		if err != nil {
+			return nil, fmt.Errorf("Load group '%v' of input '%v' failed: %v", group, input.Name, err)
+		}
+		t, err := backend.LoadContent(input.Source(), content)
+		if err != nil {
+			return nil, fmt.Errorf("Load group '%v' of input '%v' failed: %v", group, input.Name, err)
+		}
+		tree.Merge(t)
+	}
+
+	return tree, nil
+}
+
+// SaveTranslation save translation into JSON backend, not implemented
+func (backend *Backend) SaveTranslation(t *i18nmod.Translation) error {
+	return errors.New("not implemented")
+}
+
+// DeleteTranslation delete translation into JSON backend, not implemented
+func (backend *Backend) DeleteTranslation(t *i18nmod.Translation) error {
+	return errors.New("not implemented")
+}
+
+func (backend *Backend) GetFiles() map[string]map[string][]*Input {
+	return backend.inputs
+}
+
+func (backend *Backend) ListGroups() []string {
+	keys := make([]string, 0, len(backend.inputs))
+	for k := range backend.inputs {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (backend *Backend) ListLanguages() (langs []string) {
+	st := set.New(set.NonThreadSafe)
+	for group := range backend.inputs {
+		for lang := range backend.inputs[group] {
+			st.Add(lang)
+		}
+	}
+
+	st.Each(func(item interface{}) bool {
+		langs = append(langs, item.(string))
+		return true
+	})
+
+	return langs
+}
+
+func (backend *Backend) AddFileToGroup(group string, reader i18nyaml.Reader, files ...string) error {
+	for _, f := range files {
+		if err := backend.addInput("file", group, fileLang(f), "", reader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadDir adds the ".json" files of path. The group is the directory and the
+// locale is the file name.
+func (backend *Backend) LoadDir(path string) (errs []error) {
+	err := i18nmod.WalkDirExt("", path, func(group string, items []string) error {
+		group = i18nmod.FormatGroupName(group)
+		for _, item := range items {
+			item := item
+			err := backend.addInput("file", group, fileLang(item), item, func() ([]byte, error) {
+				return ioutil.ReadFile(item)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, ".json")
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+func fileLang(name string) string {
+	return strings.TrimSuffix(filepath.Base(name), ".json")
+}
+
+func (backend *Backend) AddInput(group, lang string, reader func() ([]byte, error)) (err error) {
+	return backend.addInput("raw", group, lang, "", reader)
+}
+
+func (backend *Backend) addInput(typ, group, lang, pth string, reader func() ([]byte, error)) (err error) {
+	if lang, err = i18nmod.FormatLang(lang); err != nil {
+		return
+	}
+
+	if _, ok := backend.inputs[group]; !ok {
+		backend.inputs[group] = make(map[string][]*Input)
+	}
+
+	if typ != "" {
+		typ = "+" + typ
+	}
+
+	backend.inputs[group][lang] = append(backend.inputs[group][lang], &Input{
+		Name:   "json" + typ + "://" + group + "[" + lang + "]",
+		Reader: reader,
+		Path:   pth,
+	})
+	return nil
+}
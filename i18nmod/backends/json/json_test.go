@@ -0,0 +1,74 @@
+package json_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/json"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+func TestLoadDir(t *testing.T) {
+	backend := json.New()
+	if errs := backend.LoadDir("tests"); errs != nil {
+		t.Fatal(errs)
+	}
+	other := yaml.New()
+	other.AddInput("other", "en", func() ([]byte, error) {
+		return []byte("bye: Bye\n"), nil
+	})
+
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	tr.AddBackend(other)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tr.NewContext("en")
+	for key, expected := range map[string]string{
+		"messages.hello":      "Hello",
+		"messages.user.name":  "User Name",
+		"messages.user.title": "Hello",
+		"other.bye":           "Bye",
+	} {
+		if got := ctx.T(key).Get(); got != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, got)
+		}
+	}
+	if got := ctx.T("messages.user.greet").Data(map[string]string{"Name": "Ann"}).Get(); got != "Hi Ann" {
+		t.Errorf("expected %q, got %q", "Hi Ann", got)
+	}
+	for n, expected := range map[int]string{0: "no items", 1: "one item", 5: "5 items"} {
+		if got := ctx.T("messages.items").Count(n).Get(); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+	if got := tr.NewContext("zh-CN").T("messages.user.name").Get(); got != "用户名" {
+		t.Errorf("expected %q, got %q", "用户名", got)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, content := range []string{`[]`, `{"a": "b"} {}`, `{"a": }`} {
+		if _, err := json.Decode([]byte(content)); err == nil {
+			t.Errorf("%s: expected error", content)
+		}
+	}
+	slice, err := json.Decode([]byte(`{"b": "1", "a": {"2": "x"}, "c*": {"1": "x", "007": "y"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slice[0].Key != "b" || slice[1].Key != "a" || slice[2].Key != "c*" {
+		t.Errorf("keys order not kept: %v", slice)
+	}
+	// only the integer keys of the plural cases are int
+	if key := slice[1].Value.(yaml2.MapSlice)[0].Key; key != "2" {
+		t.Errorf("expected key %q, got %#v", "2", key)
+	}
+	cases := slice[2].Value.(yaml2.MapSlice)
+	if cases[0].Key != 1 || cases[1].Key != "007" {
+		t.Errorf("invalid case keys %#v", cases)
+	}
+}
@@ -0,0 +1,457 @@
+// Package sql is the backend of the translations stored in a database, by
+// database/sql. The translations are editable at runtime by SaveTranslation,
+// DeleteTranslation and SaveLink.
+//
+// The schema, with the default table names, is:
+//
+//	CREATE TABLE i18n_translations (
+//		group_name VARCHAR(255) NOT NULL,
+//		locale     VARCHAR(35) NOT NULL,
+//		key_name   VARCHAR(255) NOT NULL,
+//		-- plain, template, message, alias, link or plural
+//		kind       VARCHAR(16) NOT NULL,
+//		-- the text, template, ICU message, alias or link target. Empty for
+//		-- the plurals.
+//		value      TEXT NOT NULL,
+//		PRIMARY KEY (group_name, locale, key_name)
+//	);
+//
+//	CREATE TABLE i18n_plural_cases (
+//		group_name VARCHAR(255) NOT NULL,
+//		locale     VARCHAR(35) NOT NULL,
+//		key_name   VARCHAR(255) NOT NULL,
+//		-- 1 for the ordinal cases
+//		ordinal    INTEGER NOT NULL,
+//		-- the CLDR category ("one"), count ("0") or expression ("=5")
+//		case_name  VARCHAR(64) NOT NULL,
+//		-- 1 if the value is a template
+//		template   INTEGER NOT NULL,
+//		value      TEXT NOT NULL,
+//		PRIMARY KEY (group_name, locale, key_name, ordinal, case_name)
+//	);
+//
+// The translations loaded have the "sql://group[locale]" source, used by
+// SaveTranslation and DeleteTranslation to find their locale.
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/logging"
+	path_helpers "github.com/moisespsena-go/path-helpers"
+)
+
+var log = logging.GetOrCreateLogger(path_helpers.GetCalledDir())
+
+var _ i18nmod.Backend = &Backend{}
+
+// The kinds of the translations.
+const (
+	KindPlain    = "plain"
+	KindTemplate = "template"
+	KindMessage  = "message"
+	KindAlias    = "alias"
+	KindLink     = "link"
+	KindPlural   = "plural"
+)
+
+// Backend SQL backend
+type Backend struct {
+	DB *sql.DB
+	// Table is the translations table, "i18n_translations" by default.
+	Table string
+	// CasesTable is the plural cases table, "i18n_plural_cases" by default.
+	CasesTable string
+	// Placeholder returns the bind parameter n, starting at 1. The default is
+	// "?". Use Dollar for PostgreSQL.
+	Placeholder func(n int) string
+}
+
+// New new SQL backend for I18n
+func New(db *sql.DB) *Backend {
+	return &Backend{DB: db, Table: "i18n_translations", CasesTable: "i18n_plural_cases"}
+}
+
+// Dollar returns the PostgreSQL bind parameter n, like "$1".
+func Dollar(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// SourceName returns the source of the translations of group and locale.
+func SourceName(group, locale string) string {
+	return "sql://" + group + "[" + locale + "]"
+}
+
+// query replaces the "?" parameters of q by the Placeholder ones and the
+// "{t}" and "{c}" names by the tables.
+func (backend *Backend) query(q string) string {
+	q = strings.NewReplacer("{t}", backend.Table, "{c}", backend.CasesTable).Replace(q)
+	if backend.Placeholder == nil {
+		return q
+	}
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, c := range q {
+		if c == '?' {
+			n++
+			b.WriteString(backend.Placeholder(n))
+		} else {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// Schema returns the statements that create the tables.
+func (backend *Backend) Schema() []string {
+	return []string{
+		backend.query(`CREATE TABLE IF NOT EXISTS {t} (
+	group_name VARCHAR(255) NOT NULL,
+	locale VARCHAR(35) NOT NULL,
+	key_name VARCHAR(255) NOT NULL,
+	kind VARCHAR(16) NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (group_name, locale, key_name)
+)`),
+		backend.query(`CREATE TABLE IF NOT EXISTS {c} (
+	group_name VARCHAR(255) NOT NULL,
+	locale VARCHAR(35) NOT NULL,
+	key_name VARCHAR(255) NOT NULL,
+	ordinal INTEGER NOT NULL,
+	case_name VARCHAR(64) NOT NULL,
+	template INTEGER NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (group_name, locale, key_name, ordinal, case_name)
+)`),
+	}
+}
+
+// CreateTables executes the Schema statements.
+func (backend *Backend) CreateTables() error {
+	for _, q := range backend.Schema() {
+		if _, err := backend.DB.Exec(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (backend *Backend) distinct(column string) (values []string) {
+	rows, err := backend.DB.Query(backend.query("SELECT DISTINCT " + column + " FROM {t}"))
+	if err != nil {
+		log.Errorf("list %s failed: %v", column, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v string
+		if err = rows.Scan(&v); err != nil {
+			log.Errorf("list %s failed: %v", column, err)
+			return
+		}
+		values = append(values, v)
+	}
+	if err = rows.Err(); err != nil {
+		log.Errorf("list %s failed: %v", column, err)
+	}
+	return
+}
+
+// ListGroups returns the groups of the translations. The errors are logged.
+func (backend *Backend) ListGroups() []string {
+	return backend.distinct("group_name")
+}
+
+// ListLanguages returns the locales of the translations. The errors are
+// logged.
+func (backend *Backend) ListLanguages() []string {
+	return backend.distinct("locale")
+}
+
+func (backend *Backend) LoadTranslations(language string, group string) (tree *i18nmod.Tree, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("Load group '%v' of locale '%v' failed: %v", group, language, err)
+		}
+	}()
+
+	source := SourceName(group, language)
+	translations := map[string]*i18nmod.Translation{}
+	links := map[string]string{}
+
+	rows, err := backend.DB.Query(backend.query("SELECT key_name, kind, value FROM {t} WHERE group_name = ? AND locale = ?"), group, language)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key, kind, value string
+		if err = rows.Scan(&key, &kind, &value); err != nil {
+			return
+		}
+		t := &i18nmod.Translation{Key: key, Source: &source}
+		switch kind {
+		case KindPlain:
+			t.Value = value
+		case KindTemplate:
+			t.Value = value
+			if t.Template, err = i18nmod.ParseLazyTemplate(value); err != nil {
+				return nil, fmt.Errorf("Parse translation [%v] template failed: %v", key, err)
+			}
+		case KindMessage:
+			t.Value = value
+			if t.Message, err = i18nmod.ParseMessage(value); err != nil {
+				return nil, fmt.Errorf("Parse translation [%v] message failed: %v", key, err)
+			}
+		case KindAlias:
+			t.Alias = value
+		case KindLink:
+			links[key] = value
+			continue
+		case KindPlural:
+		default:
+			return nil, fmt.Errorf("Invalid kind %q of translation [%v]", kind, key)
+		}
+		translations[key] = t
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	if rows, err = backend.DB.Query(backend.query("SELECT key_name, ordinal, case_name, template, value FROM {c} WHERE group_name = ? AND locale = ?"), group, language); err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			key, name, value  string
+			ordinal, template bool
+		)
+		if err = rows.Scan(&key, &ordinal, &name, &template, &value); err != nil {
+			return
+		}
+		t, ok := translations[key]
+		if !ok || name == "" {
+			continue
+		}
+		var v interface{} = value
+		if template {
+			if v, err = i18nmod.ParseLazyTemplate(value); err != nil {
+				return nil, fmt.Errorf("Parse translation [%v] case %q template failed: %v", key, name, err)
+			}
+		}
+		p := &t.Plural
+		if ordinal {
+			p = &t.Ordinal
+		}
+		if *p == nil {
+			*p = &i18nmod.Plural{Ordinal: ordinal}
+		}
+		if i, err := strconv.Atoi(name); err == nil {
+			(*p).AddCase(i, v)
+		} else {
+			(*p).AddCase(name, v)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	tree = &i18nmod.Tree{}
+	for _, t := range translations {
+		tree.Add(t)
+	}
+	// the links are set after the translations, as the YAML backend does
+	keys := make([]string, 0, len(links))
+	for key := range links {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		node := tree.Tree(key)
+		node.Link(links[key])
+		node.Parent.Links[node.Name].Source = &source
+	}
+	return tree, nil
+}
+
+// locale returns the group and the locale of the translation source.
+func locale(t *i18nmod.Translation) (group, locale string, err error) {
+	if t.Group == nil || t.Source == nil {
+		return "", "", fmt.Errorf("Translation %q has no group or source", t.Key)
+	}
+	group = *t.Group
+	prefix := "sql://" + group + "["
+	if s := *t.Source; strings.HasPrefix(s, prefix) && strings.HasSuffix(s, "]") {
+		return group, s[len(prefix) : len(s)-1], nil
+	}
+	return "", "", fmt.Errorf("Source %q of translation [%v.%v] is not a SQL source", *t.Source, group, t.Key)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type caseRow struct {
+	ordinal  bool
+	name     string
+	template bool
+	value    string
+}
+
+func pluralRows(p *i18nmod.Plural, ordinal bool) (rows []caseRow, err error) {
+	if p == nil {
+		return
+	}
+	add := func(name string, value interface{}) error {
+		switch v := value.(type) {
+		case string:
+			rows = append(rows, caseRow{ordinal, name, false, v})
+		case *i18nmod.LazyTemplate:
+			rows = append(rows, caseRow{ordinal, name, true, v.Text})
+		default:
+			return fmt.Errorf("unsupported value %T of case %q", value, name)
+		}
+		return nil
+	}
+	for k, v := range p.Cases {
+		switch k {
+		case "p", "s":
+			// aliases of "other" and "one"
+			continue
+		}
+		if err = add(fmt.Sprint(k), v); err != nil {
+			return
+		}
+	}
+	for k, v := range p.ExpCases {
+		if err = add(string(k.Cond)+k.Format+k.Value, v); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// SaveTranslation inserts or replaces the translation of the group and locale
+// of its source. The selects are not supported.
+func (backend *Backend) SaveTranslation(t *i18nmod.Translation) error {
+	group, locale, err := locale(t)
+	if err != nil {
+		return err
+	}
+	return backend.Save(group, locale, t)
+}
+
+// Save inserts or replaces the translation of group and locale.
+func (backend *Backend) Save(group, locale string, t *i18nmod.Translation) (err error) {
+	var (
+		kind  = KindPlain
+		value = t.Value
+		cases []caseRow
+	)
+	switch {
+	case t.Select != nil:
+		return fmt.Errorf("Save translation [%v.%v]: select is not supported", group, t.Key)
+	case t.Alias != "":
+		kind, value = KindAlias, t.Alias
+	case t.Message != nil:
+		kind, value = KindMessage, t.Message.Pattern
+	case t.Template != nil:
+		kind, value = KindTemplate, t.Template.Text
+	case t.ValueTemplate != nil:
+		kind = KindTemplate
+	case t.Plural != nil || t.Ordinal != nil:
+		kind, value = KindPlural, ""
+		var ordinal []caseRow
+		if cases, err = pluralRows(t.Plural, false); err == nil {
+			ordinal, err = pluralRows(t.Ordinal, true)
+		}
+		if err != nil {
+			return fmt.Errorf("Save translation [%v.%v]: %v", group, t.Key, err)
+		}
+		cases = append(cases, ordinal...)
+	}
+
+	return backend.replace(group, locale, t.Key, kind, value, cases)
+}
+
+// SaveLink inserts or replaces the link of key to the to key.
+func (backend *Backend) SaveLink(group, locale, key, to string) error {
+	return backend.replace(group, locale, key, KindLink, to, nil)
+}
+
+func (backend *Backend) replace(group, locale, key, kind, value string, cases []caseRow) (err error) {
+	tx, err := backend.DB.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if err = backend.delete(tx, group, locale, key); err != nil {
+		return
+	}
+	if _, err = tx.Exec(backend.query("INSERT INTO {t} (group_name, locale, key_name, kind, value) VALUES (?, ?, ?, ?, ?)"),
+		group, locale, key, kind, value); err != nil {
+		return
+	}
+	for _, c := range cases {
+		if _, err = tx.Exec(backend.query("INSERT INTO {c} (group_name, locale, key_name, ordinal, case_name, template, value) VALUES (?, ?, ?, ?, ?, ?, ?)"),
+			group, locale, key, boolInt(c.ordinal), c.name, boolInt(c.template), c.value); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (backend *Backend) delete(tx *sql.Tx, group, locale, key string) (err error) {
+	for _, q := range []string{"DELETE FROM {t} WHERE group_name = ? AND locale = ? AND key_name = ?",
+		"DELETE FROM {c} WHERE group_name = ? AND locale = ? AND key_name = ?"} {
+		if _, err = tx.Exec(backend.query(q), group, locale, key); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// DeleteTranslation removes the translation, or link, of the group and locale
+// of its source.
+func (backend *Backend) DeleteTranslation(t *i18nmod.Translation) error {
+	group, locale, err := locale(t)
+	if err != nil {
+		return err
+	}
+	return backend.Delete(group, locale, t.Key)
+}
+
+// Delete removes the translation, or link, of key.
+func (backend *Backend) Delete(group, locale, key string) (err error) {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	tx, err := backend.DB.Begin()
+	if err != nil {
+		return
+	}
+	if err = backend.delete(tx, group, locale, key); err != nil {
+		tx.Rollback()
+		return
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,122 @@
+package sql_test
+
+import (
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	i18nsql "github.com/moisespsena-go/i18n-modular/i18nmod/backends/sql"
+	_ "modernc.org/sqlite"
+)
+
+func backend(t *testing.T) *i18nsql.Backend {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each connection has its own memory database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	backend := i18nsql.New(db)
+	if err = backend.CreateTables(); err != nil {
+		t.Fatal(err)
+	}
+	return backend
+}
+
+func TestBackend(t *testing.T) {
+	backend := backend(t)
+	save := func(group, locale string, tn *i18nmod.Translation) {
+		if err := backend.Save(group, locale, tn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("messages", "en", &i18nmod.Translation{Key: "hello", Value: "Hello"})
+	save("messages", "en", &i18nmod.Translation{Key: "user.greet", Template: i18nmod.NewLazyTemplate("Hi {{.Name}}")})
+	save("messages", "en", &i18nmod.Translation{Key: "title", Alias: ".hello"})
+	save("messages", "en", &i18nmod.Translation{Key: "files", Plural: &i18nmod.Plural{Cases: map[interface{}]interface{}{
+		0:       "no files",
+		"one":   "one file",
+		"other": i18nmod.NewLazyTemplate("{{count}} files"),
+	}}})
+	save("messages", "en", &i18nmod.Translation{Key: "place", Ordinal: &i18nmod.Plural{Ordinal: true, Cases: map[interface{}]interface{}{
+		"one":   i18nmod.NewLazyTemplate("{{count}}st"),
+		"other": i18nmod.NewLazyTemplate("{{count}}th"),
+	}}})
+	save("messages", "pt-BR", &i18nmod.Translation{Key: "hello", Value: "Olá"})
+	save("admin", "en", &i18nmod.Translation{Key: "users.name", Value: "Name"})
+	if err := backend.SaveLink("admin", "en", "profile", "users"); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, locales := backend.ListGroups(), backend.ListLanguages()
+	sort.Strings(groups)
+	sort.Strings(locales)
+	if len(groups) != 2 || groups[0] != "admin" || groups[1] != "messages" {
+		t.Errorf("invalid groups %v", groups)
+	}
+	if len(locales) != 2 || locales[0] != "en" || locales[1] != "pt-BR" {
+		t.Errorf("invalid locales %v", locales)
+	}
+
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := tr.NewContext("en")
+	for key, expected := range map[string]string{
+		"messages.hello":     "Hello",
+		"messages.title":     "Hello",
+		"admin.users.name":   "Name",
+		"admin.profile.name": "Name",
+	} {
+		if got := ctx.T(key).Get(); got != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, got)
+		}
+	}
+	if got := ctx.T("messages.user.greet").Data(map[string]string{"Name": "Ann"}).Get(); got != "Hi Ann" {
+		t.Errorf("expected %q, got %q", "Hi Ann", got)
+	}
+	for n, expected := range map[int]string{0: "no files", 1: "one file", 3: "3 files"} {
+		if got := ctx.T("messages.files").Count(n).Get(); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+	if got := ctx.T("messages.place").Ordinal(1).Get(); got != "1st" {
+		t.Errorf("expected %q, got %q", "1st", got)
+	}
+	if got := tr.NewContext("pt-BR").T("messages.hello").Get(); got != "Olá" {
+		t.Errorf("expected %q, got %q", "Olá", got)
+	}
+
+	// the loaded translations are saved back by their source
+	tn, _ := tr.Get("messages", "hello", "en")
+	group := "messages"
+	tn.Group, tn.Value = &group, "Hi"
+	if err := backend.SaveTranslation(tn); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := tr.Get("messages", "files", "en")
+	files.Group = &group
+	if err := backend.DeleteTranslation(files); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := backend.LoadTranslations("en", "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tn := tree.Children["hello"].T; tn.Value != "Hi" {
+		t.Errorf("expected %q, got %q", "Hi", tn.Value)
+	}
+	if tree.Children["files"] != nil {
+		t.Error("translation not deleted")
+	}
+
+	source := "messages.yaml"
+	if err := backend.SaveTranslation(&i18nmod.Translation{Group: &group, Source: &source, Key: "x"}); err == nil {
+		t.Error("expected error of not SQL source")
+	}
+}
@@ -1,7 +1,260 @@
 package repository
 
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	i18nyaml "github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+)
+
 type CompilerOptions struct {
+	// Output is the generated file path
 	Output string
+	// Package is the package name of the generated file. If empty, it is the
+	// name of the Output directory.
 	Package string
 }
 
+// GenerateTranslations returns the Go source of all group translations of
+// backend. The YAML is parsed and its templates and messages are validated
+// now. The generated code registers the trees with i18nmod.RegisterCompiled,
+// so Translator.LoadCompiled sets them with Translator.NewGroup, without
+// parsing YAML. Its Register function sets them into a single Translator. The
+// templates are parsed on their first use.
+func GenerateTranslations(backend *i18nyaml.Backend, options *CompilerOptions) ([]byte, error) {
+	pkg := options.Package
+	if pkg == "" {
+		pkg = strings.ToLower(GoName(filepath.Base(filepath.Dir(options.Output))))
+	}
+
+	g := &goWriter{sources: map[string]int{}}
+	var groups bytes.Buffer
+
+	files := backend.GetFiles()
+	groupNames := make([]string, 0, len(files))
+	for group := range files {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+
+	var register []string
+	for _, group := range groupNames {
+		locales := make([]string, 0, len(files[group]))
+		for locale := range files[group] {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+
+		for _, locale := range locales {
+			tree, err := backend.LoadTranslations(locale, group)
+			if err != nil {
+				return nil, err
+			}
+			fn := fmt.Sprintf("group%d", len(register))
+			register = append(register, fmt.Sprintf("tr.NewGroup(%q, %q, %s)", locale, group, fn))
+
+			fmt.Fprintf(&groups, "\n// %s adds the %q group translations of %q.\nfunc %s(t *i18nmod.Tree) {\n", fn, group, locale, fn)
+			if err = g.tree(&groups, "", tree, map[*i18nmod.Tree]bool{}); err != nil {
+				return nil, fmt.Errorf("Compile group %q of %q failed: %v", group, locale, err)
+			}
+			for _, link := range g.links {
+				groups.WriteString(link)
+			}
+			g.links = nil
+			groups.WriteString("}\n")
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by i18nmod compile. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	b.WriteString("import \"github.com/moisespsena-go/i18n-modular/i18nmod\"\n\n")
+	b.WriteString("func init() {\n\ti18nmod.RegisterCompiled(Register)\n}\n\n")
+	b.WriteString("var sources = []string{\n")
+	for _, source := range g.sourceList {
+		fmt.Fprintf(&b, "\t%q,\n", source)
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("// Register sets the compiled translations into tr.\nfunc Register(tr *i18nmod.Translator) {\n")
+	for _, line := range register {
+		b.WriteString("\t" + line + "\n")
+	}
+	b.WriteString("}\n")
+	b.Write(groups.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Format generated code failed: %v", err)
+	}
+	return src, nil
+}
+
+// CompileTranslations writes the GenerateTranslations source into the Output
+// file.
+func CompileTranslations(backend *i18nyaml.Backend, options *CompilerOptions) error {
+	src, err := GenerateTranslations(backend, options)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(options.Output, src, 0644)
+}
+
+type goWriter struct {
+	sources    map[string]int
+	sourceList []string
+	links      []string
+}
+
+func (g *goWriter) source(s *string) string {
+	i, ok := g.sources[*s]
+	if !ok {
+		i = len(g.sourceList)
+		g.sources[*s] = i
+		g.sourceList = append(g.sourceList, *s)
+	}
+	return fmt.Sprintf("&sources[%d]", i)
+}
+
+// tree writes the Add calls of the tree translations, sorted by key. The links
+// are written after them, because their targets are walked by their own path.
+func (g *goWriter) tree(b *bytes.Buffer, prefix string, t *i18nmod.Tree, path map[*i18nmod.Tree]bool) error {
+	if path[t] {
+		return nil
+	}
+	path[t] = true
+	defer delete(path, t)
+
+	names := make([]string, 0, len(t.Children))
+	for name := range t.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if link, ok := t.Links[name]; ok {
+			g.links = append(g.links, fmt.Sprintf("\tt.Tree(%q).Link(%q)\n", prefix+name, link.To))
+			continue
+		}
+		child := t.Children[name]
+		if child.T != nil {
+			s, err := g.translation(prefix+name, child.T)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(b, "\tt.Add(%s)\n", s)
+		}
+		if err := g.tree(b, prefix+name+".", child, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *goWriter) translation(key string, t *i18nmod.Translation) (string, error) {
+	fields := []string{fmt.Sprintf("Key: %q", key)}
+	if t.Value != "" {
+		fields = append(fields, fmt.Sprintf("Value: %q", t.Value))
+	}
+	if t.Template != nil {
+		fields = append(fields, fmt.Sprintf("Template: i18nmod.NewLazyTemplate(%q)", t.Template.Text))
+	} else if t.ValueTemplate != nil {
+		if t.Value == "" {
+			return "", fmt.Errorf("the template of %q has no text", key)
+		}
+		fields = append(fields, fmt.Sprintf("Template: i18nmod.NewLazyTemplate(%q)", t.Value))
+	}
+	if t.Alias != "" {
+		fields = append(fields, fmt.Sprintf("Alias: %q", t.Alias))
+	}
+	if t.Message != nil {
+		fields = append(fields, fmt.Sprintf("Message: i18nmod.MustParseMessage(%q)", t.Message.Pattern))
+	}
+	for _, c := range []struct {
+		name  string
+		value interface{}
+		isNil bool
+	}{{"Plural", t.Plural, t.Plural == nil}, {"Ordinal", t.Ordinal, t.Ordinal == nil}, {"Select", t.Select, t.Select == nil}} {
+		if c.isNil {
+			continue
+		}
+		v, err := goValue(c.value)
+		if err != nil {
+			return "", fmt.Errorf("%s of %q: %v", c.name, key, err)
+		}
+		fields = append(fields, c.name+": "+v)
+	}
+	if t.Source != nil {
+		fields = append(fields, "Source: "+g.source(t.Source))
+	}
+	return "&i18nmod.Translation{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// goValue returns the Go expression of the case value.
+func goValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprintf("%#v", v), nil
+	case *i18nmod.LazyTemplate:
+		return fmt.Sprintf("i18nmod.NewLazyTemplate(%q)", v.Text), nil
+	case *i18nmod.Plural:
+		var fields []string
+		if len(v.Cases) > 0 {
+			cases, err := goMap(v.Cases)
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, "Cases: map[interface{}]interface{}{"+cases+"}")
+		}
+		if len(v.ExpCases) > 0 {
+			var items []string
+			for k, c := range v.ExpCases {
+				cv, err := goValue(c)
+				if err != nil {
+					return "", err
+				}
+				items = append(items, fmt.Sprintf("{Cond: %q, Value: %q, Format: %q}: %s", rune(k.Cond), k.Value, k.Format, cv))
+			}
+			sort.Strings(items)
+			fields = append(fields, "ExpCases: map[i18nmod.PluralKeyCount]interface{}{"+strings.Join(items, ", ")+"}")
+		}
+		if v.Ordinal {
+			fields = append(fields, "Ordinal: true")
+		}
+		return "&i18nmod.Plural{" + strings.Join(fields, ", ") + "}", nil
+	case *i18nmod.Select:
+		cases := make(map[interface{}]interface{}, len(v.Cases))
+		for k, c := range v.Cases {
+			cases[k] = c
+		}
+		s, err := goMap(cases)
+		if err != nil {
+			return "", err
+		}
+		return "&i18nmod.Select{Cases: map[string]interface{}{" + s + "}}", nil
+	}
+	return "", fmt.Errorf("unsupported value %T", value)
+}
+
+func goMap(m map[interface{}]interface{}) (string, error) {
+	var items []string
+	for k, v := range m {
+		ks, err := goValue(k)
+		if err != nil {
+			return "", err
+		}
+		vs, err := goValue(v)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, ks+": "+vs)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", "), nil
+}
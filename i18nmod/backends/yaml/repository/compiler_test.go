@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml/repository"
+)
+
+func TestGenerateTranslations(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("app", "en", func() ([]byte, error) {
+		return []byte(`
+title: Title
+home@: .title
+menu:
+  top&: /title
+greet~: "Hello {{.Name}}"
+place*:
+  1: one place
+  other~: "{{count}} places"
+files!: "{count, plural, one {# file} other {# files}}"
+`), nil
+	})
+
+	src, err := repository.GenerateTranslations(backend, &repository.CompilerOptions{Package: "locales"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"package locales\n",
+		"i18nmod.RegisterCompiled(Register)",
+		`tr.NewGroup("en", "app", group0)`,
+		`t.Add(&i18nmod.Translation{Key: "title", Value: "Title", Source: &sources[0]})`,
+		`t.Add(&i18nmod.Translation{Key: "home", Alias: ".title", Source: &sources[0]})`,
+		`Key: "greet", Value: "Hello {{.Name}}", Template: i18nmod.NewLazyTemplate("Hello {{.Name}}")`,
+		`Plural: &i18nmod.Plural{Cases: map[interface{}]interface{}{"other": i18nmod.NewLazyTemplate("{{count}} places"), 1: "one place"}}`,
+		`Message: i18nmod.MustParseMessage("{count, plural, one {# file} other {# files}}")`,
+		`t.Tree("menu.top").Link("/title")`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("%q not generated:\n%s", expected, src)
+		}
+	}
+
+	backend.AddInput("app", "pt", func() ([]byte, error) {
+		return []byte("bad~: \"{{.Name\"\n"), nil
+	})
+	if _, err = repository.GenerateTranslations(backend, &repository.CompilerOptions{Package: "locales"}); err == nil {
+		t.Error("invalid template compiled")
+	}
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	i18nyaml "github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+)
+
+// keyInfo is the usage of a key, merged from the YAML of all locales.
+type keyInfo struct {
+	key     string
+	count   bool
+	ordinal bool
+	selects int
+	fields  []string
+}
+
+func (k *keyInfo) addFields(fields ...string) {
+	for _, f := range fields {
+		var has bool
+		for _, cur := range k.fields {
+			if has = cur == f; has {
+				break
+			}
+		}
+		if !has {
+			k.fields = append(k.fields, f)
+		}
+	}
+}
+
+type keysReader struct {
+	keys  map[string]*keyInfo
+	icu   bool
+	links []keyLink
+}
+
+// keyLink is a link ("&" suffix) from the key path to the target path.
+type keyLink struct {
+	from, to string
+}
+
+func (r *keysReader) get(scopes []string) *keyInfo {
+	key := strings.Join(scopes, ".")
+	k, ok := r.keys[key]
+	if !ok {
+		k = &keyInfo{key: key}
+		r.keys[key] = k
+	}
+	return k
+}
+
+func (r *keysReader) read(source string, value yaml.MapSlice, scopes []string) error {
+	for _, e := range value {
+		key := fmt.Sprint(e.Key)
+		if len(scopes) == 0 && key == i18nyaml.FormatKey {
+			continue
+		}
+		if key == "" {
+			return fmt.Errorf("%v: empty key in %q", source, strings.Join(scopes, "."))
+		}
+
+		name := key[0 : len(key)-1]
+		child := append(scopes[0:len(scopes):len(scopes)], name)
+
+		switch s := fmt.Sprint(e.Value); key[len(key)-1] {
+		case '*', '#', '?':
+			if cases, ok := e.Value.(yaml.MapSlice); ok {
+				k := r.get(child)
+				if err := r.readCases(source, k, key[len(key)-1], cases, 1); err != nil {
+					return err
+				}
+				continue
+			}
+		case '&':
+			// the keys of the link are copied from its target by addLinks
+			r.links = append(r.links, keyLink{strings.Join(child, "."), linkTarget(scopes, s)})
+			continue
+		case '@':
+			r.get(child)
+			continue
+		case '~':
+			fields, err := i18nmod.TemplateFields(s)
+			if err != nil {
+				return fmt.Errorf("%v: parse template of %q failed: %v", source, strings.Join(child, "."), err)
+			}
+			r.get(child).addFields(fields...)
+			continue
+		case '!':
+			if err := r.readMessage(source, child, s); err != nil {
+				return err
+			}
+			continue
+		}
+
+		child[len(child)-1] = key
+		switch v := e.Value.(type) {
+		case yaml.MapSlice:
+			if err := r.read(source, v, child); err != nil {
+				return err
+			}
+		default:
+			if r.icu {
+				if err := r.readMessage(source, child, fmt.Sprint(v)); err != nil {
+					return err
+				}
+			} else {
+				r.get(child)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *keysReader) readMessage(source string, scopes []string, pattern string) error {
+	msg, err := i18nmod.ParseMessage(pattern)
+	if err != nil {
+		return fmt.Errorf("%v: %v", source, err)
+	}
+	k := r.get(scopes)
+	for _, arg := range msg.Args() {
+		if arg == "count" {
+			k.count = true
+		} else {
+			k.addFields(arg)
+		}
+	}
+	return nil
+}
+
+// linkTarget returns the key path of the link target to, from the link
+// scopes, as Tree.Link resolves it: "/" is the group root and each leading
+// "." is the parent scope.
+func linkTarget(scopes []string, to string) string {
+	if strings.HasPrefix(to, "/") {
+		return to[1:]
+	}
+	scopes = scopes[0:len(scopes):len(scopes)]
+	for strings.HasPrefix(to, ".") {
+		if len(scopes) > 0 {
+			scopes = scopes[0 : len(scopes)-1]
+		}
+		to = to[1:]
+	}
+	return strings.Join(append(scopes, to), ".")
+}
+
+// addLinks adds the keys of the links: the copies of the target keys under the
+// link path. The links to other links are followed, but not their cycles.
+func (r *keysReader) addLinks() {
+	for range r.links {
+		var added bool
+		for _, l := range r.links {
+			for key, k := range r.keys {
+				if key != l.to && !strings.HasPrefix(key, l.to+".") {
+					continue
+				}
+				from := l.from + key[len(l.to):]
+				if _, ok := r.keys[from]; ok || strings.HasPrefix(from, l.to+".") {
+					continue
+				}
+				link := *k
+				link.key = from
+				link.fields = append([]string{}, k.fields...)
+				r.keys[from], added = &link, true
+			}
+		}
+		if !added {
+			return
+		}
+	}
+}
+
+// readCases reads the plural ("*"), ordinal ("#") and select ("?") cases of k
+// and their nested cases. depth is the select level.
+func (r *keysReader) readCases(source string, k *keyInfo, typ byte, cases yaml.MapSlice, depth int) error {
+	switch typ {
+	case '*':
+		k.count = true
+	case '#':
+		k.ordinal = true
+	case '?':
+		if depth > k.selects {
+			k.selects = depth
+		}
+		depth++
+	}
+
+	for _, e := range cases {
+		key := fmt.Sprint(e.Key)
+		if key == "" {
+			continue
+		}
+		switch key[len(key)-1] {
+		case '~':
+			fields, err := i18nmod.TemplateFields(fmt.Sprint(e.Value))
+			if err != nil {
+				return fmt.Errorf("%v: parse template of %q case %q failed: %v", source, k.key, key, err)
+			}
+			k.addFields(fields...)
+		case '*', '#', '?':
+			if nested, ok := e.Value.(yaml.MapSlice); ok {
+				if err := r.readCases(source, k, key[len(key)-1], nested, depth); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GoName returns the exported Go identifier of the key: "user.first_name"
+// gives "UserFirstName".
+func GoName(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range key {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(c) {
+			b.WriteByte('N')
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	if b.Len() == 0 {
+		return "Key"
+	}
+	return b.String()
+}
+
+// GenerateKeys returns the Go source of the typed accessors of the group keys
+// read from the YAML inputs of backend. Each key has a function, like
+// "func UserName(ctx i18nmod.Context) string". The plurals receive the count,
+// the selects receive the choices and the templates and ICU messages receive
+// a params struct with their data fields. The keys of the links are the keys
+// of their targets under the link path.
+func GenerateKeys(backend *i18nyaml.Backend, group string, options *CompilerOptions) ([]byte, error) {
+	locales, ok := backend.GetFiles()[group]
+	if !ok {
+		return nil, fmt.Errorf("Group %q not found", group)
+	}
+
+	r := &keysReader{keys: map[string]*keyInfo{}}
+	for _, inputs := range locales {
+		for _, input := range inputs {
+			content, err := input.Reader()
+			if err != nil {
+				return nil, fmt.Errorf("Read %v failed: %v", *input.Source(), err)
+			}
+			var slice yaml.MapSlice
+			if err = yaml.Unmarshal(content, &slice); err != nil {
+				return nil, fmt.Errorf("Parse %v failed: %v", *input.Source(), err)
+			}
+			r.icu = false
+			for _, e := range slice {
+				if e.Key == i18nyaml.FormatKey && fmt.Sprint(e.Value) == "icu" {
+					r.icu = true
+				}
+			}
+			if err = r.read(*input.Source(), slice, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	r.addLinks()
+
+	pkg := options.Package
+	if pkg == "" {
+		pkg = strings.ToLower(GoName(filepath.Base(filepath.Dir(options.Output))))
+	}
+
+	keys := make([]*keyInfo, 0, len(r.keys))
+	for _, k := range r.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].key < keys[j].key
+	})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by i18nmod gen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/moisespsena-go/i18n-modular/i18nmod\"\n\n")
+	fmt.Fprintf(&b, "// I18nGroup is the translations group of the keys.\nconst I18nGroup = %q\n", group)
+
+	names := map[string]bool{"I18nGroup": true}
+	name := func(base string) string {
+		name := base
+		for i := 2; names[name]; i++ {
+			name = base + strconv.Itoa(i)
+		}
+		names[name] = true
+		return name
+	}
+
+	for _, k := range keys {
+		if k.count && k.ordinal {
+			writeAccessor(&b, k, name(GoName(k.key)), "count", ".Count(count)")
+			writeAccessor(&b, k, name(GoName(k.key)+"Ordinal"), "n", ".Ordinal(n)")
+		} else if k.ordinal {
+			writeAccessor(&b, k, name(GoName(k.key)), "n", ".Ordinal(n)")
+		} else if k.count {
+			writeAccessor(&b, k, name(GoName(k.key)), "count", ".Count(count)")
+		} else {
+			writeAccessor(&b, k, name(GoName(k.key)), "", "")
+		}
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Format generated code failed: %v", err)
+	}
+	return src, nil
+}
+
+func writeAccessor(b *bytes.Buffer, k *keyInfo, name, countParam, countCall string) {
+	params := []string{"ctx i18nmod.Context"}
+	calls := countCall
+	if countParam != "" {
+		params = append(params, countParam+" interface{}")
+	}
+
+	if k.selects > 0 {
+		var choices []string
+		for i := 1; i <= k.selects; i++ {
+			if k.selects == 1 {
+				choices = append(choices, "choice")
+			} else {
+				choices = append(choices, "choice"+strconv.Itoa(i))
+			}
+		}
+		params = append(params, strings.Join(choices, ", ")+" interface{}")
+		calls += ".Select(" + strings.Join(choices, ", ") + ")"
+	}
+
+	if len(k.fields) > 0 {
+		typ := name + "Params"
+		fmt.Fprintf(b, "\n// %s are the data fields of the %q translation.\ntype %s struct {\n", typ, k.key, typ)
+		var data []string
+		for _, f := range k.fields {
+			fmt.Fprintf(b, "\t%s interface{}\n", GoName(f))
+			data = append(data, fmt.Sprintf("%q: params.%s", f, GoName(f)))
+		}
+		b.WriteString("}\n")
+		params = append(params, "params "+typ)
+		calls += ".Data(map[string]interface{}{" + strings.Join(data, ", ") + "})"
+	}
+
+	fmt.Fprintf(b, "\n// %s translates the %q key.\nfunc %s(%s) string {\n\treturn ctx.T(I18nGroup + %q)%s.Get()\n}\n",
+		name, k.key, name, strings.Join(params, ", "), "."+k.key, calls)
+}
+
+// CompileKeys writes the GenerateKeys source into the Output file.
+func CompileKeys(backend *i18nyaml.Backend, group string, options *CompilerOptions) error {
+	src, err := GenerateKeys(backend, group, options)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(options.Output, src, 0644)
+}
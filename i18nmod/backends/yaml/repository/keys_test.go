@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml/repository"
+)
+
+func TestGenerateKeys(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("app", "en", func() ([]byte, error) {
+		return []byte(`
+user:
+  name: User Name
+  greet~: "Hello {{.first_name}} {{.Last}}"
+home@: .user.name
+place*:
+  one: one place
+  other~: "{{count}} places"
+place#:
+  one~: "{{count}}st place"
+  other~: "{{count}}th place"
+invite?:
+  female~: "{{.Host}} invited you to her party"
+  other: invited you
+files!: "{count, plural, one {# file} other {# files}} in {dir}"
+profile&: user
+menu:
+  top&: /place
+`), nil
+	})
+	backend.AddInput("app", "pt-BR", func() ([]byte, error) {
+		return []byte(`
+user:
+  name: Nome
+  email: E-mail
+`), nil
+	})
+
+	src, err := repository.GenerateKeys(backend, "app", &repository.CompilerOptions{Output: "i18n/keys.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"package i18n\n",
+		`const I18nGroup = "app"`,
+		"func UserName(ctx i18nmod.Context) string {\n\treturn ctx.T(I18nGroup + \".user.name\").Get()",
+		"func UserEmail(ctx i18nmod.Context) string",
+		"func Home(ctx i18nmod.Context) string",
+		"type UserGreetParams struct {\n\tFirstName interface{}\n\tLast      interface{}\n}",
+		"func UserGreet(ctx i18nmod.Context, params UserGreetParams) string {\n\treturn ctx.T(I18nGroup + \".user.greet\")" +
+			".Data(map[string]interface{}{\"first_name\": params.FirstName, \"Last\": params.Last}).Get()",
+		"func Place(ctx i18nmod.Context, count interface{}) string {\n\treturn ctx.T(I18nGroup + \".place\").Count(count).Get()",
+		"func PlaceOrdinal(ctx i18nmod.Context, n interface{}) string {\n\treturn ctx.T(I18nGroup + \".place\").Ordinal(n).Get()",
+		"func Invite(ctx i18nmod.Context, choice interface{}, params InviteParams) string",
+		".Select(choice).Data(map[string]interface{}{\"Host\": params.Host})",
+		"func Files(ctx i18nmod.Context, count interface{}, params FilesParams) string",
+		"func ProfileName(ctx i18nmod.Context) string {\n\treturn ctx.T(I18nGroup + \".profile.name\").Get()",
+		"func ProfileGreet(ctx i18nmod.Context, params ProfileGreetParams) string",
+		"func MenuTop(ctx i18nmod.Context, count interface{}) string {\n\treturn ctx.T(I18nGroup + \".menu.top\").Count(count).Get()",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("%q not generated:\n%s", expected, src)
+		}
+	}
+}
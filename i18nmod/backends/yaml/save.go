@@ -0,0 +1,316 @@
+package yaml
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"gopkg.in/yaml.v2"
+)
+
+// suffixes are the key suffixes of the translation values
+var suffixes = []string{"", "~", "@", "&", "*", "#", "?", "!"}
+
+// SaveTranslation writes the translation into its file, found by its Group and
+// Source. The key is replaced at its position, or added to the end of its
+// scope, keeping the order of the other keys.
+func (backend *Backend) SaveTranslation(t *i18nmod.Translation) error {
+	return backend.update(t, func(file yaml.MapSlice, icu bool) (yaml.MapSlice, error) {
+		items, err := translationItems(t, icu)
+		if err != nil {
+			return nil, err
+		}
+		return setKey(file, strings.Split(t.Key, "."), items), nil
+	})
+}
+
+// DeleteTranslation removes the translation from its file, found by its Group
+// and Source. The scopes left empty are removed too.
+func (backend *Backend) DeleteTranslation(t *i18nmod.Translation) error {
+	return backend.update(t, func(file yaml.MapSlice, icu bool) (yaml.MapSlice, error) {
+		return setKey(file, strings.Split(t.Key, "."), nil), nil
+	})
+}
+
+// input returns the file input of the translation.
+func (backend *Backend) input(t *i18nmod.Translation) (*Input, error) {
+	if t.Group == nil || t.Source == nil {
+		return nil, fmt.Errorf("Translation %q has no group or source", t.Key)
+	}
+	for _, inputs := range backend.GetFiles()[*t.Group] {
+		for _, input := range inputs {
+			if *input.Source() == *t.Source {
+				if input.Path == "" {
+					return nil, fmt.Errorf("Input %q of translation [%v.%v] is not a file", input.Name, *t.Group, t.Key)
+				}
+				return input, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Source %q of translation [%v.%v] not found", *t.Source, *t.Group, t.Key)
+}
+
+func (backend *Backend) update(t *i18nmod.Translation, f func(file yaml.MapSlice, icu bool) (yaml.MapSlice, error)) error {
+	input, err := backend.input(t)
+	if err != nil {
+		return err
+	}
+
+	backend.writeMu.Lock()
+	defer backend.writeMu.Unlock()
+
+	content, err := input.Reader()
+	if err != nil {
+		return err
+	}
+	var file yaml.MapSlice
+	if err = yaml.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("Parse %q failed: %v", input.Path, err)
+	}
+
+	var icu bool
+	for _, e := range file {
+		if e.Key == FormatKey && fmt.Sprint(e.Value) == "icu" {
+			icu = true
+		}
+	}
+
+	if file, err = f(file, icu); err != nil {
+		return fmt.Errorf("Update translation [%v.%v] of %q failed: %v", *t.Group, t.Key, input.Path, err)
+	}
+	if content, err = yaml.Marshal(file); err != nil {
+		return err
+	}
+	return writeFile(input.Path, content)
+}
+
+// writeFile replaces the file atomically, writing a temporary file and renaming
+// it.
+func writeFile(pth string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(pth); err == nil {
+		mode = info.Mode()
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(pth), "."+filepath.Base(pth)+".")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err = f.Write(content); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp, pth)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// setKey replaces the items of the key path in scope, at the position of its
+// first current item. If items is empty, the key is removed and so are the
+// scopes left empty.
+func setKey(scope yaml.MapSlice, path []string, items yaml.MapSlice) yaml.MapSlice {
+	name := path[0]
+	if len(path) > 1 {
+		for i, e := range scope {
+			if fmt.Sprint(e.Key) == name {
+				if child, ok := e.Value.(yaml.MapSlice); ok {
+					if child = setKey(child, path[1:], items); len(child) == 0 {
+						return append(scope[0:i:i], scope[i+1:]...)
+					}
+					scope[i].Value = child
+					return scope
+				}
+			}
+		}
+		if len(items) == 0 {
+			return scope
+		}
+		return append(scope, yaml.MapItem{Key: name, Value: setKey(nil, path[1:], items)})
+	}
+
+	var (
+		result yaml.MapSlice
+		added  bool
+	)
+	for _, e := range scope {
+		if isItemOf(fmt.Sprint(e.Key), name) {
+			if !added {
+				result = append(result, mergeItems(items, scope, name)...)
+				added = true
+			}
+			continue
+		}
+		result = append(result, e)
+	}
+	if !added {
+		result = append(result, items...)
+	}
+	return result
+}
+
+func isItemOf(key, name string) bool {
+	for _, suffix := range suffixes {
+		if key == name+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeItems orders the cases of the new items as in the current items of
+// name in scope.
+func mergeItems(items, scope yaml.MapSlice, name string) yaml.MapSlice {
+	for i, item := range items {
+		cases, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		for _, e := range scope {
+			if e.Key == item.Key {
+				if old, ok := e.Value.(yaml.MapSlice); ok {
+					items[i].Value = orderAs(cases, old)
+				}
+			}
+		}
+	}
+	return items
+}
+
+func orderAs(cases, old yaml.MapSlice) yaml.MapSlice {
+	pos := map[string]int{}
+	for i, e := range old {
+		pos[fmt.Sprint(e.Key)] = i
+	}
+	sort.SliceStable(cases, func(i, j int) bool {
+		pi, iok := pos[fmt.Sprint(cases[i].Key)]
+		pj, jok := pos[fmt.Sprint(cases[j].Key)]
+		switch {
+		case iok && jok:
+			return pi < pj
+		case iok:
+			return true
+		}
+		return false
+	})
+	return cases
+}
+
+// translationItems returns the YAML items of the translation, with the key
+// suffixes of its values.
+func translationItems(t *i18nmod.Translation, icu bool) (items yaml.MapSlice, err error) {
+	name := t.Key[strings.LastIndex(t.Key, ".")+1:]
+	add := func(suffix string, value interface{}) {
+		items = append(items, yaml.MapItem{Key: name + suffix, Value: value})
+	}
+
+	switch {
+	case t.Alias != "":
+		add("@", t.Alias)
+	case t.Message != nil:
+		if icu {
+			add("", t.Message.Pattern)
+		} else {
+			add("!", t.Message.Pattern)
+		}
+	case t.Template != nil:
+		add("~", t.Template.Text)
+	case t.ValueTemplate != nil:
+		if t.Value == "" {
+			return nil, fmt.Errorf("the template has no text")
+		}
+		add("~", t.Value)
+	case t.Plural == nil && t.Ordinal == nil && t.Select == nil:
+		add("", t.Value)
+	}
+
+	for _, c := range []struct {
+		suffix string
+		value  interface{}
+		isNil  bool
+	}{{"*", t.Plural, t.Plural == nil}, {"#", t.Ordinal, t.Ordinal == nil}, {"?", t.Select, t.Select == nil}} {
+		if c.isNil {
+			continue
+		}
+		var cases yaml.MapSlice
+		if cases, err = casesMap(c.value); err != nil {
+			return
+		}
+		add(c.suffix, cases)
+	}
+	return
+}
+
+// casesMap returns the cases of the Plural or Select as YAML map.
+func casesMap(value interface{}) (cases yaml.MapSlice, err error) {
+	add := func(key, v interface{}) error {
+		k := fmt.Sprint(key)
+		switch cv := v.(type) {
+		case *i18nmod.LazyTemplate:
+			cases = append(cases, yaml.MapItem{Key: k + "~", Value: cv.Text})
+		case *i18nmod.Plural:
+			nested, err := casesMap(cv)
+			if err != nil {
+				return err
+			}
+			suffix := "*"
+			if cv.Ordinal {
+				suffix = "#"
+			}
+			cases = append(cases, yaml.MapItem{Key: k + suffix, Value: nested})
+		case *i18nmod.Select:
+			nested, err := casesMap(cv)
+			if err != nil {
+				return err
+			}
+			cases = append(cases, yaml.MapItem{Key: k + "?", Value: nested})
+		case string, bool, int, int64, float64:
+			cases = append(cases, yaml.MapItem{Key: key, Value: cv})
+		default:
+			return fmt.Errorf("case %v: unsupported value %T", key, v)
+		}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case *i18nmod.Plural:
+		for k, c := range v.Cases {
+			// "p" and "s" set the "other" and "one" cases too
+			if (k == "other" && v.Cases["p"] == c) || (k == "one" && v.Cases["s"] == c) {
+				continue
+			}
+			if err = add(k, c); err != nil {
+				return
+			}
+		}
+		for k, c := range v.ExpCases {
+			if err = add(string(k.Cond)+k.Format+k.Value, c); err != nil {
+				return
+			}
+		}
+	case *i18nmod.Select:
+		for k, c := range v.Cases {
+			if err = add(k, c); err != nil {
+				return
+			}
+		}
+	}
+
+	sort.SliceStable(cases, func(i, j int) bool {
+		return fmt.Sprint(cases[i].Key) < fmt.Sprint(cases[j].Key)
+	})
+	return
+}
@@ -0,0 +1,98 @@
+package yaml_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+)
+
+func TestSaveTranslation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "i18nmod-save")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pth := filepath.Join(dir, "messages", "en.yaml")
+	if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `hello: Hello
+user:
+  name: User Name
+  greet~: Hi {{.Name}}
+  email: Email
+bye: Bye
+`
+	if err = ioutil.WriteFile(pth, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := yaml.New()
+	if errs := backend.LoadDir(dir); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	group := "messages"
+
+	for _, tn := range []*i18nmod.Translation{
+		{Group: &group, Source: &pth, Key: "user.name", Value: "Name"},
+		{Group: &group, Source: &pth, Key: "user.greet", Plural: &i18nmod.Plural{Cases: map[interface{}]interface{}{
+			"one":   i18nmod.NewLazyTemplate("one {{.Name}}"),
+			"other": "many",
+		}}},
+		{Group: &group, Source: &pth, Key: "new.key", Alias: ".hello"},
+	} {
+		if err = backend.SaveTranslation(tn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = backend.DeleteTranslation(&i18nmod.Translation{Group: &group, Source: &pth, Key: "bye"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `hello: Hello
+user:
+  name: Name
+  greet*:
+    one~: one {{.Name}}
+    other: many
+  email: Email
+new:
+  key@: .hello
+`
+	data, err := ioutil.ReadFile(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, data)
+	}
+	if info, err := os.Stat(pth); err != nil {
+		t.Error(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode not kept: %v", info.Mode())
+	}
+
+	if err = backend.DeleteTranslation(&i18nmod.Translation{Group: &group, Source: &pth, Key: "new.key"}); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := backend.LoadTranslations("en", group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Children["new"] != nil {
+		t.Error("empty scope not removed")
+	}
+	if tn := tree.Children["user"].Children["name"].T; tn == nil || tn.Value != "Name" {
+		t.Errorf("saved translation not loaded: %v", tn)
+	}
+
+	raw := "raw"
+	if err = backend.SaveTranslation(&i18nmod.Translation{Group: &group, Source: &raw, Key: "x"}); err == nil {
+		t.Error("expected error of unknown source")
+	}
+}
@@ -0,0 +1,122 @@
+package yaml
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/logging"
+	path_helpers "github.com/moisespsena-go/path-helpers"
+)
+
+var log = logging.GetOrCreateLogger(path_helpers.GetCalledDir())
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls the files added by Backend.LoadDir and reloads the group
+// translations of the changed files into the Translator. The files created in
+// the LoadDir paths are added by Backend.Rescan and loaded too.
+type Watcher struct {
+	Backend    *Backend
+	Translator *i18nmod.Translator
+	Interval   time.Duration
+	// OnError is called with the reload errors. If nil, errors are logged.
+	OnError func(err error)
+
+	states   map[string]fileState
+	checked  bool
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Watch starts a Watcher that checks the LoadDir files every interval.
+func (backend *Backend) Watch(tr *i18nmod.Translator, interval time.Duration) *Watcher {
+	w := &Watcher{Backend: backend, Translator: tr, Interval: interval}
+	w.Start()
+	return w
+}
+
+func (w *Watcher) Start() {
+	if w.Interval <= 0 {
+		w.Interval = time.Second
+	}
+	w.states = map[string]fileState{}
+	w.checked = false
+	w.stopOnce = sync.Once{}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	w.Check()
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				for _, err := range w.Check() {
+					if w.OnError != nil {
+						w.OnError(err)
+					} else {
+						log.Error(err)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the watcher and waits for the running check.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// Check reloads the groups of the files changed, created or deleted since the
+// previous call. The deleted files are removed from the Backend.
+func (w *Watcher) Check() (errs []error) {
+	_, errs = w.Backend.Rescan()
+	for group, locales := range w.Backend.GetFiles() {
+		for lang, inputs := range locales {
+			var changed bool
+			for _, input := range inputs {
+				if input.Path == "" {
+					continue
+				}
+				info, err := os.Stat(input.Path)
+				if os.IsNotExist(err) {
+					// the deleted files are removed, and their group reloaded
+					w.Backend.RemoveFile(input.Path)
+					delete(w.states, input.Path)
+					changed = true
+					continue
+				} else if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				state := fileState{info.ModTime(), info.Size()}
+				if old, ok := w.states[input.Path]; ok && old != state || !ok && w.checked {
+					changed = true
+				}
+				w.states[input.Path] = state
+			}
+			if changed {
+				if err := w.Translator.Reload(lang, group); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	w.checked = true
+	return
+}
@@ -0,0 +1,112 @@
+package yaml_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+)
+
+func TestWatcherReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "i18nmod-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pth := filepath.Join(dir, "messages", "en.yaml")
+	if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(pth, []byte("hello: Hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := yaml.New()
+	if errs := backend.LoadDir(dir); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err = tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := make(chan string, 10)
+	tr.AfterGroupLoad("messages", func(lang string, db *i18nmod.ChildDB) {
+		if t := db.Get("hello"); t != nil {
+			loaded <- t.Value
+		} else {
+			loaded <- ""
+		}
+	})
+	<-loaded
+
+	errs := make(chan error, 10)
+	w := &yaml.Watcher{Backend: backend, Translator: tr, Interval: 10 * time.Millisecond,
+		OnError: func(err error) { errs <- err }}
+	w.Start()
+	defer w.Stop()
+
+	if err = ioutil.WriteFile(pth, []byte("hello: Hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(pth, future, future)
+
+	select {
+	case value := <-loaded:
+		if value != "Hi" {
+			t.Errorf("expected reloaded value %q, got %q", "Hi", value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("translations not reloaded")
+	}
+
+	if got := tr.NewContext("en").T("messages.hello").Get(); got != "Hi" {
+		t.Errorf("expected %q, got %q", "Hi", got)
+	}
+
+	// the files created after Watch are loaded too
+	pth = filepath.Join(dir, "messages", "pt-BR.yaml")
+	if err = ioutil.WriteFile(pth, []byte("hello: Olá\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case value := <-loaded:
+		if value != "Olá" {
+			t.Errorf("expected loaded value %q, got %q", "Olá", value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("new file not loaded")
+	}
+	if got := tr.NewContext("pt-BR").T("messages.hello").Get(); got != "Olá" {
+		t.Errorf("expected %q, got %q", "Olá", got)
+	}
+
+	// the deleted files are removed once, and their group reloaded
+	if err = os.Remove(pth); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case value := <-loaded:
+		if value != "" {
+			t.Errorf("expected no value, got %q", value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deleted file not unloaded")
+	}
+	if files := backend.GetFiles()["messages"]; len(files["pt-BR"]) != 0 {
+		t.Errorf("expected no pt-BR inputs, got %v", files["pt-BR"])
+	}
+	w.Stop()
+	select {
+	case err := <-errs:
+		t.Errorf("unexpected error %v", err)
+	default:
+	}
+}
@@ -1,17 +1,17 @@
 package yaml
 
 import (
-	"errors"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"strings"
+	"sync"
 
 	"path/filepath"
 	"strconv"
 
 	"github.com/moisespsena-go/i18n-modular/i18nmod"
 	"github.com/moisespsena/template/text/template"
-	"github.com/nicksnyder/go-i18n/i18n/language"
 	"gopkg.in/fatih/set.v0"
 	"gopkg.in/yaml.v2"
 )
@@ -20,7 +20,7 @@ var _ i18nmod.Backend = &Backend{}
 
 // New new YAML backend for I18n
 func New() *Backend {
-	return &Backend{make(map[string]map[string][]*Input)}
+	return &Backend{inputs: make(map[string]map[string][]*Input)}
 }
 
 type FileReader func(name string) ([]byte, error)
@@ -29,21 +29,37 @@ type Reader func() ([]byte, error)
 type Input struct {
 	Name   string
 	Reader Reader
+	// Path is the file path of inputs added by LoadDir
+	Path string
+}
+
+// Source returns the file path of the input, or its name.
+func (i *Input) Source() *string {
+	if i.Path != "" {
+		return &i.Path
+	}
+	return &i.Name
 }
 
 // Backend YAML backend
 type Backend struct {
+	// mu guards inputs and dirs
+	mu     sync.RWMutex
 	inputs map[string]map[string][]*Input
+	// dirs are the LoadDir paths
+	dirs []string
+	// writeMu serializes the file writes
+	writeMu sync.Mutex
 }
 
-func mapToPlural(scope []string, parentkey string, value yaml.MapSlice) (*i18nmod.Plural, error) {
-	var plural = &i18nmod.Plural{}
+func mapToPlural(scope []string, parentkey string, value yaml.MapSlice, ordinal bool) (*i18nmod.Plural, error) {
+	var plural = &i18nmod.Plural{Ordinal: ordinal}
 
 	for _, e := range value {
 		key := fmt.Sprint(e.Key)
 
 		if strings.HasSuffix(key, "~") {
-			v, err := template.New("").Parse(fmt.Sprint(e.Value))
+			v, err := i18nmod.ParseLazyTemplate(fmt.Sprint(e.Value))
 			if err != nil {
 				return nil, fmt.Errorf("Parse translation [%v.%v.%v] template failed: %v",
 					strings.Join(scope, "."), parentkey, key, err)
@@ -52,9 +68,18 @@ func mapToPlural(scope []string, parentkey string, value yaml.MapSlice) (*i18nmo
 			key = key[0 : len(key)-1]
 
 			if i, err := strconv.Atoi(key); err == nil {
-				plural.AddCase(i, v.Executor())
+				plural.AddCase(i, v)
 			} else {
-				plural.AddCase(key, v.Executor())
+				plural.AddCase(key, v)
+			}
+		} else if name, v, ok, err := mapToCase(scope, parentkey, key, e.Value); ok {
+			if err != nil {
+				return nil, err
+			}
+			if i, err := strconv.Atoi(name); err == nil {
+				plural.AddCase(i, v)
+			} else {
+				plural.AddCase(name, v)
 			}
 		} else {
 			plural.AddCase(e.Key, e.Value)
@@ -64,19 +89,91 @@ func mapToPlural(scope []string, parentkey string, value yaml.MapSlice) (*i18nmo
 	return plural, nil
 }
 
+func mapToSelect(scope []string, parentkey string, value yaml.MapSlice) (*i18nmod.Select, error) {
+	var sel = &i18nmod.Select{}
+
+	for _, e := range value {
+		key := fmt.Sprint(e.Key)
+
+		if strings.HasSuffix(key, "~") {
+			v, err := i18nmod.ParseLazyTemplate(fmt.Sprint(e.Value))
+			if err != nil {
+				return nil, fmt.Errorf("Parse translation [%v.%v.%v] template failed: %v",
+					strings.Join(scope, "."), parentkey, key, err)
+			}
+			sel.AddCase(key[0:len(key)-1], v)
+		} else if name, v, ok, err := mapToCase(scope, parentkey, key, e.Value); ok {
+			if err != nil {
+				return nil, err
+			}
+			sel.AddCase(name, v)
+		} else {
+			sel.AddCase(key, fmt.Sprint(e.Value))
+		}
+	}
+
+	return sel, nil
+}
+
+// mapToCase parses the nested plural ("*" suffix), ordinal ("#" suffix) and
+// select ("?" suffix) cases. ok is false for other keys.
+func mapToCase(scope []string, parentkey, key string, value interface{}) (name string, v interface{}, ok bool, err error) {
+	mps, isMap := value.(yaml.MapSlice)
+	if !isMap || key == "" {
+		return
+	}
+
+	name = key[0 : len(key)-1]
+	scope = append(append([]string{}, scope...), parentkey)
+
+	switch key[len(key)-1] {
+	case '*':
+		v, err = mapToPlural(scope, name, mps, false)
+	case '#':
+		v, err = mapToPlural(scope, name, mps, true)
+	case '?':
+		v, err = mapToSelect(scope, name, mps)
+	default:
+		return
+	}
+	return name, v, true, err
+}
+
 type importer struct {
 	tree  i18nmod.Tree
 	links map[string]string
+	// icu parses the plain values as ICU MessageFormat
+	icu bool
 }
 
+// FormatKey is the top-level key that sets the format of the plain values of a
+// file. The "icu" format parses them as ICU MessageFormat, like the keys with
+// the "!" suffix.
+const FormatKey = "~format"
+
 func (i *importer) Add(t ...*i18nmod.Translation) {
 	for _, t := range t {
+		if cur := i.tree.Tree(t.Key).T; cur != nil && cur.Ordinal != nil && t.Ordinal == nil {
+			t.Ordinal = cur.Ordinal
+		}
 		i.tree.Add(t)
 	}
 }
 
-func (i *importer) Link(from, to string) {
-	i.tree.Tree(from).Link(to)
+// AddOrdinal sets the ordinal cases of the translation of key, keeping its
+// other values.
+func (i *importer) AddOrdinal(name *string, key string, ordinal *i18nmod.Plural) {
+	node := i.tree.Tree(key)
+	if node.T == nil {
+		node.T = &i18nmod.Translation{Key: key, Source: name}
+	}
+	node.T.Ordinal = ordinal
+}
+
+func (i *importer) Link(name *string, from, to string) {
+	node := i.tree.Tree(from)
+	node.Link(to)
+	node.Parent.Links[node.Name].Source = name
 }
 
 func (i *importer) Import(name *string, value interface{}, scopes []string) (err error) {
@@ -85,11 +182,39 @@ func (i *importer) Import(name *string, value interface{}, scopes []string) (err
 		for _, e := range v {
 			key := fmt.Sprint(e.Key)
 
-			if strings.HasSuffix(key, "*") {
+			if strings.HasSuffix(key, "#") {
 				switch mps := e.Value.(type) {
 				case yaml.MapSlice:
 					key := key[0 : len(key)-1]
-					plural, err := mapToPlural(scopes, key, mps)
+					ordinal, err := mapToPlural(scopes, key, mps, true)
+
+					if err != nil {
+						return err
+					}
+
+					i.AddOrdinal(name, strings.Join(append(scopes, key), "."), ordinal)
+				}
+			} else if strings.HasSuffix(key, "?") {
+				switch mps := e.Value.(type) {
+				case yaml.MapSlice:
+					key := key[0 : len(key)-1]
+					sel, err := mapToSelect(scopes, key, mps)
+
+					if err != nil {
+						return err
+					}
+
+					i.Add(&i18nmod.Translation{
+						Key:    strings.Join(append(scopes, key), "."),
+						Select: sel,
+						Source: name,
+					})
+				}
+			} else if strings.HasSuffix(key, "*") {
+				switch mps := e.Value.(type) {
+				case yaml.MapSlice:
+					key := key[0 : len(key)-1]
+					plural, err := mapToPlural(scopes, key, mps, false)
 
 					if err != nil {
 						return err
@@ -122,12 +247,12 @@ func (i *importer) Import(name *string, value interface{}, scopes []string) (err
 
 				if strings.HasSuffix(k, "~") {
 					k = k[0 : len(k)-1]
-					t, err := template.New("").Parse(value[1])
+					t, err := i18nmod.ParseLazyTemplate(value[1])
 					if err != nil {
 						return fmt.Errorf("Parse translation [%v][%d][1] template failed: %v",
 							strings.Join(scopes, "."), i, err)
 					}
-					v = t.CreateExecutor()
+					v = t
 				}
 
 				plural.AddCase(k, v)
@@ -155,12 +280,12 @@ func (i *importer) Import(name *string, value interface{}, scopes []string) (err
 				if s, ok := k.(string); ok {
 					if strings.HasSuffix(s, "~") {
 						k = s[0 : len(s)-1]
-						t, err := template.New("").Parse(v.(string))
+						t, err := i18nmod.ParseLazyTemplate(v.(string))
 						if err != nil {
 							return fmt.Errorf("Parse translation [%v][%d][1] template failed: %v",
 								strings.Join(scopes, "."), i, err)
 						}
-						v = t.CreateExecutor()
+						v = t
 						k = s
 					}
 				}
@@ -190,6 +315,7 @@ func (i *importer) Import(name *string, value interface{}, scopes []string) (err
 
 			i.Add(&i18nmod.Translation{
 				Key:           strings.Join(scopes, "."),
+				Value:         v,
 				ValueTemplate: tpl,
 				Source:        name,
 			})
@@ -203,8 +329,24 @@ func (i *importer) Import(name *string, value interface{}, scopes []string) (err
 				Source: name,
 			})
 		} else if strings.HasSuffix(key, "&") {
-			key = key[0 : len(key)-1]
-			i.Link(key, v)
+			scopes[len(scopes)-1] = key[0 : len(key)-1]
+			i.Link(name, strings.Join(scopes, "."), v)
+		} else if strings.HasSuffix(key, "!") || i.icu {
+			key = strings.TrimSuffix(key, "!")
+			scopes[len(scopes)-1] = key
+
+			msg, err := i18nmod.ParseMessage(v)
+			if err != nil {
+				return fmt.Errorf("Parse translation [%v] message failed: %v",
+					strings.Join(scopes, "."), err)
+			}
+
+			i.Add(&i18nmod.Translation{
+				Key:     strings.Join(scopes, "."),
+				Value:   v,
+				Message: msg,
+				Source:  name,
+			})
 		} else {
 			i.Add(&i18nmod.Translation{
 				Key:    strings.Join(scopes, "."),
@@ -224,25 +366,42 @@ func (backend *Backend) LoadContent(name *string, content []byte) (tree *i18nmod
 	var slice yaml.MapSlice
 
 	if err = yaml.Unmarshal(content, &slice); err == nil {
-		imp := &importer{links: map[string]string{}}
-		err = imp.Import(name, slice, []string{})
-		if err != nil {
-			return
-		}
-		tree = &imp.tree
+		tree, err = Import(name, slice)
 	}
 
 	return
 }
 
+// Import returns the tree of the translations of slice, read with the YAML key
+// suffixes. name is the source of the translations.
+func Import(name *string, slice yaml.MapSlice) (tree *i18nmod.Tree, err error) {
+	imp := &importer{links: map[string]string{}}
+	for j, e := range slice {
+		if e.Key == FormatKey {
+			switch format := fmt.Sprint(e.Value); format {
+			case "icu":
+				imp.icu = true
+			default:
+				return nil, fmt.Errorf("Invalid format %q of %v", format, *name)
+			}
+			slice = append(slice[0:j:j], slice[j+1:]...)
+			break
+		}
+	}
+	if err = imp.Import(name, slice, []string{}); err != nil {
+		return
+	}
+	return &imp.tree, nil
+}
+
 func (backend *Backend) LoadTranslations(language string, group string) (*i18nmod.Tree, error) {
 	tree := &i18nmod.Tree{}
 
-	if gfiles, ok := backend.inputs[group]; ok {
+	if gfiles, ok := backend.GetFiles()[group]; ok {
 		if inputs, ok := gfiles[language]; ok {
 			for _, input := range inputs {
 				if content, err := input.Reader(); err == nil {
-					t, err := backend.LoadContent(&input.Name, content)
+					t, err := backend.LoadContent(input.Source(), content)
 					if err != nil {
 						return nil, fmt.Errorf("Load group '%v' of input '%v' failed: %v", group, input.Name, err)
 					}
@@ -257,21 +416,24 @@ func (backend *Backend) LoadTranslations(language string, group string) (*i18nmo
 	return tree, nil
 }
 
-// SaveTranslation save translation into YAML backend, not implemented
-func (backend *Backend) SaveTranslation(t *i18nmod.Translation) error {
-	return errors.New("not implemented")
-}
-
-// DeleteTranslation delete translation into YAML backend, not implemented
-func (backend *Backend) DeleteTranslation(t *i18nmod.Translation) error {
-	return errors.New("not implemented")
-}
-
+// GetFiles returns a copy of the inputs, by group and language.
 func (backend *Backend) GetFiles() map[string]map[string][]*Input {
-	return backend.inputs
+	backend.mu.RLock()
+	defer backend.mu.RUnlock()
+
+	files := make(map[string]map[string][]*Input, len(backend.inputs))
+	for group, locales := range backend.inputs {
+		files[group] = make(map[string][]*Input, len(locales))
+		for lang, inputs := range locales {
+			files[group][lang] = append([]*Input{}, inputs...)
+		}
+	}
+	return files
 }
 
 func (backend *Backend) ListGroups() []string {
+	backend.mu.RLock()
+	defer backend.mu.RUnlock()
 	keys := make([]string, len(backend.inputs))
 
 	i := 0
@@ -284,6 +446,8 @@ func (backend *Backend) ListGroups() []string {
 }
 
 func (backend *Backend) ListLanguages() (langs []string) {
+	backend.mu.RLock()
+	defer backend.mu.RUnlock()
 	st := set.New(set.NonThreadSafe)
 	for group := range backend.inputs {
 		for lang := range backend.inputs[group] {
@@ -301,41 +465,151 @@ func (backend *Backend) ListLanguages() (langs []string) {
 
 func (backend *Backend) AddFileToGroup(group string, reader Reader, files ...string) error {
 	for _, f := range files {
-		lang := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(f), ".yaml"), ".yml")
-		return backend.addInput("file", group, lang, reader)
+		if err := backend.addInput("file", group, fileLang(f), "", reader); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (backend *Backend) LoadDir(path string) (errs []error) {
-	err := i18nmod.WalkDir(".", path, func(group string, items []string) error {
-		return backend.AddFileToGroup(i18nmod.FormatGroupName(group), func() (bytes []byte, e error) {
-			return ioutil.ReadFile(path)
-		}, items...)
-	})
-	if err != nil {
+	backend.mu.Lock()
+	backend.dirs = append(backend.dirs, path)
+	backend.mu.Unlock()
+	if err := backend.loadDir(path, nil); err != nil {
 		errs = append(errs, err)
 	}
 	return
 }
 
-func (backend *Backend) AddInput(group, lang string, reader func() ([]byte, error)) (err error) {
-	return backend.addInput("raw", group, lang, reader)
+// RemoveFile removes the inputs of the file pth. It returns false if there is
+// none.
+func (backend *Backend) RemoveFile(pth string) (removed bool) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	for group, locales := range backend.inputs {
+		for lang, inputs := range locales {
+			kept := inputs[:0:0]
+			for _, input := range inputs {
+				if input.Path == pth {
+					removed = true
+				} else {
+					kept = append(kept, input)
+				}
+			}
+			if len(kept) == 0 {
+				delete(locales, lang)
+			} else {
+				locales[lang] = kept
+			}
+		}
+		if len(locales) == 0 {
+			delete(backend.inputs, group)
+		}
+	}
+	return
 }
 
-func (backend *Backend) addInput(typ, group, lang string, reader func() ([]byte, error)) (err error) {
-	if lang != i18nmod.AnyLang {
-		langs := language.Parse(lang)
-		if l := len(langs); l == 0 || l > 1 {
-			return fmt.Errorf("Invalid language name %q", lang)
+// Rescan walks the LoadDir paths again and adds the files not added yet. It
+// returns the new inputs.
+func (backend *Backend) Rescan() (added []*Input, errs []error) {
+	backend.mu.RLock()
+	dirs := append([]string{}, backend.dirs...)
+	known := map[string]bool{}
+	for _, locales := range backend.inputs {
+		for _, inputs := range locales {
+			for _, input := range inputs {
+				if input.Path != "" {
+					known[input.Path] = true
+				}
+			}
 		}
-		lang = langs[0].Tag
-		parts := strings.Split(lang, "-")
+	}
+	backend.mu.RUnlock()
 
-		if len(parts) > 1 {
-			lang = parts[0] + "-" + strings.ToUpper(parts[1])
+	for _, dir := range dirs {
+		if err := backend.loadDir(dir, func(item string) bool {
+			return !known[item]
+		}); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	for _, locales := range backend.GetFiles() {
+		for _, inputs := range locales {
+			for _, input := range inputs {
+				if input.Path != "" && !known[input.Path] {
+					added = append(added, input)
+				}
+			}
+		}
+	}
+	return
+}
+
+// loadDir adds the files of path accepted by filter, or all if it is nil.
+func (backend *Backend) loadDir(path string, filter func(item string) bool) error {
+	return i18nmod.WalkDir("", path, func(group string, items []string) error {
+		group = i18nmod.FormatGroupName(group)
+		for _, item := range items {
+			if filter != nil && !filter(item) {
+				continue
+			}
+			item := item
+			err := backend.addInput("file", group, fileLang(item), item, func() ([]byte, error) {
+				return ioutil.ReadFile(item)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LoadFS adds the YAML files of the root directory of fsys, like LoadDir. It
+// loads the translations of an embed.FS:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	backend.LoadFS(locales, "locales")
+//
+// The fsys inputs are not written by SaveTranslation.
+func (backend *Backend) LoadFS(fsys fs.FS, root string) (errs []error) {
+	err := i18nmod.WalkFS(fsys, "", root, func(group string, items []string) error {
+		group = i18nmod.FormatGroupName(group)
+		for _, item := range items {
+			item := item
+			err := backend.addInput("fs", group, fileLang(item), "", func() ([]byte, error) {
+				return fs.ReadFile(fsys, item)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, ".yaml")
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+func fileLang(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(filepath.Base(name), ".yaml"), ".yml")
+}
+
+func (backend *Backend) AddInput(group, lang string, reader func() ([]byte, error)) (err error) {
+	return backend.addInput("raw", group, lang, "", reader)
+}
+
+func (backend *Backend) addInput(typ, group, lang, pth string, reader func() ([]byte, error)) (err error) {
+	if lang, err = i18nmod.FormatLang(lang); err != nil {
+		return
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
 
 	if _, ok := backend.inputs[group]; !ok {
 		backend.inputs[group] = make(map[string][]*Input)
@@ -349,6 +623,6 @@ func (backend *Backend) addInput(typ, group, lang string, reader func() ([]byte,
 		typ = "+" + typ
 	}
 
-	backend.inputs[group][lang] = append(backend.inputs[group][lang], &Input{"yaml" + typ + "://" + group + "[" + lang + "]", reader})
+	backend.inputs[group][lang] = append(backend.inputs[group][lang], &Input{"yaml" + typ + "://" + group + "[" + lang + "]", reader, pth})
 	return nil
 }
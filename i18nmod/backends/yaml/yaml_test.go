@@ -1,8 +1,12 @@
 package yaml_test
 
 import (
+	"embed"
+	"reflect"
 	"testing"
+	"testing/fstest"
 
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
 	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
 )
 
@@ -24,16 +28,244 @@ func TestLoadTranslations(t *testing.T) {
 	}
 
 	for locale, results := range values {
+		tree, err := backend.LoadTranslations(locale, "messages")
+		if err != nil {
+			t.Fatal(err)
+		}
+		translations := map[string]string{}
+		tree.WalkT(func(key string, translation *i18nmod.Translation) error {
+			translations[key] = translation.Value
+			return nil
+		})
 		for _, result := range results {
-			var found bool
-			for _, translation := range backend {
-				if (translation.Locale == locale) && (translation.Key == result[0]) && (translation.Value == result[1]) {
-					found = true
-				}
-			}
-			if !found {
+			if translations[result[0]] != result[1] {
 				t.Errorf("failed to found translation %v for %v", result[0], locale)
 			}
 		}
 	}
 }
+
+func TestOrdinal(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("ranking", "en", func() ([]byte, error) {
+		return []byte(`
+place*:
+  one: one place
+  other~: "{{count}} places"
+place#:
+  one~: "{{count}}st place"
+  two~: "{{count}}nd place"
+  few~: "{{count}}rd place"
+  other~: "{{count}}th place"
+file*:
+  one: one file
+  other~: "{{count}} files"
+`), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tr.NewContext("en")
+	for n, expected := range map[int]string{1: "1st place", 2: "2nd place", 3: "3rd place", 4: "4th place", 11: "11th place", 22: "22nd place"} {
+		if got := ctx.T("ranking.place").Ordinal(n).Get(); got != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, got)
+		}
+	}
+	if got := ctx.T("ranking.place").Count(3).Get(); got != "3 places" {
+		t.Errorf("expected %q, got %q", "3 places", got)
+	}
+	// the cardinal cases are not used as ordinal
+	if got, err := ctx.T("ranking.file").Ordinal(1).GetE(); err == nil {
+		t.Errorf("expected error, got %q", got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("messages", "en", func() ([]byte, error) {
+		return []byte(`
+replied?:
+  male: He replied
+  female~: "{{.Name}} replied, she said"
+  other: They replied
+invited?:
+  female*:
+    one~: "She invited {{count}} guest"
+    other~: "She invited {{count}} guests"
+  other*:
+    one~: "They invited {{count}} guest"
+    other~: "They invited {{count}} guests"
+`), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tr.NewContext("en")
+	for _, c := range []struct {
+		t        *i18nmod.T
+		expected string
+	}{
+		{ctx.T("messages.replied").Select("male"), "He replied"},
+		{ctx.T("messages.replied").Select("female").Data(map[string]string{"Name": "Ana"}), "Ana replied, she said"},
+		{ctx.T("messages.replied").Select("unknown"), "They replied"},
+		{ctx.T("messages.invited").Select("female").Count(1), "She invited 1 guest"},
+		{ctx.T("messages.invited").Select("male").Count(int64(3)), "They invited 3 guests"},
+	} {
+		if got := c.t.Get(); got != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, got)
+		}
+	}
+}
+
+func TestPluralSelect(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("messages", "en", func() ([]byte, error) {
+		return []byte(`
+liked*:
+  one?:
+    female: She liked it
+    other: They liked it
+  other~: "{{count}} people liked it"
+`), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tr.NewContext("en")
+	if got := ctx.T("messages.liked").Count(1).Select("female").Get(); got != "She liked it" {
+		t.Errorf("expected %q, got %q", "She liked it", got)
+	}
+	if got := ctx.T("messages.liked").Count(4).Select("female").Get(); got != "4 people liked it" {
+		t.Errorf("expected %q, got %q", "4 people liked it", got)
+	}
+}
+
+func TestMessageFormat(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("keys", "en", func() ([]byte, error) {
+		return []byte(`
+files!: "{count, plural, one {# file} other {# files}}"
+plain: "{not a message}"
+`), nil
+	})
+	backend.AddInput("file", "en", func() ([]byte, error) {
+		return []byte(`
+~format: icu
+greeting: "Hello {Name}, you are {pos, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}"
+`), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := tr.NewContext("en")
+	for _, c := range []struct {
+		t        *i18nmod.T
+		expected string
+	}{
+		{ctx.T("keys.files").Count(1), "1 file"},
+		{ctx.T("keys.files").Count(7), "7 files"},
+		{ctx.T("keys.plain"), "{not a message}"},
+		{ctx.T("file.greeting").Data(map[string]interface{}{"Name": "Ana", "pos": 2}), "Hello Ana, you are 2nd"},
+		{ctx.T("file.greeting").Data(struct{ Name string }{"Bob"}).Count(3), "ERROR: Translation [file.greeting] of locale 'en' from 'yaml+raw://file[en]' failed: missing argument \"pos\""},
+	} {
+		if got := c.t.Get(); got != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, got)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	backend := yaml.New()
+	backend.AddInput("app", "en", func() ([]byte, error) {
+		return []byte(`
+title: Title
+home@: .title
+broken@: .nothing
+a@: .b
+b@: .a
+menu:
+  help&: missing.node
+  top&: /title
+count*:
+  one: one item
+greet~: "Hello {{.Name}}, {{.Last}}"
+files*:
+  one: one file
+  other~: "{{count}} files of {{.Owner}}"
+welcome!: "Hi {User}, {count, plural, one {# item} other {# items}}"
+`), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+
+	linter := i18nmod.NewLinter(tr)
+	linter.Fields = map[string][]string{"app.greet": {"Name"}, "app.files": {"Name"}, "app.welcome": {"Name"}}
+	issues, err := linter.Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got [][2]string
+	for _, issue := range issues {
+		got = append(got, [2]string{issue.Key, string(issue.Kind)})
+		if issue.Locale != "en" || issue.Source != "yaml+raw://app[en]" {
+			t.Errorf("bad issue location: %v", issue.Error())
+		}
+	}
+	expected := [][2]string{
+		{"a", "alias-cycle"},
+		{"b", "alias-cycle"},
+		{"broken", "broken-alias"},
+		{"count", "plural-other"},
+		{"files", "template-field"},
+		{"greet", "template-field"},
+		{"menu.help", "empty-link"},
+		{"welcome", "template-field"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+//go:embed tests
+var testsFS embed.FS
+
+func TestLoadFS(t *testing.T) {
+	backend := yaml.New()
+	if errs := backend.LoadFS(testsFS, "tests"); errs != nil {
+		t.Fatal(errs)
+	}
+	backend.LoadFS(fstest.MapFS{
+		"admin/users.v2/en.yaml": {Data: []byte("title: Users\n")},
+	}, ".")
+
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	for key, expected := range map[string]string{
+		"messages.hello":       "Hello",
+		"messages.user.name":   "User Name",
+		"admin:users_v2.title": "Users",
+	} {
+		if got := tr.NewContext("en").T(key).Get(); got != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, got)
+		}
+	}
+	if got := tr.NewContext("zh-CN").T("messages.hello").Get(); got != "你好" {
+		t.Errorf("expected %q, got %q", "你好", got)
+	}
+}
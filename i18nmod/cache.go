@@ -13,7 +13,7 @@ type Cache struct {
 }
 
 func NewCache() *Cache {
-	return &Cache{Data: make(map[string]map[string]*Result), Allowed: set.New(set.ThreadSafe)}
+	return &Cache{Data: make(map[string]map[string]*Result), Allowed: set.New(set.ThreadSafe), RWMutex: &sync.RWMutex{}}
 }
 
 func (c *Cache) Allow(key string) *Cache {
@@ -0,0 +1,29 @@
+package i18nmod
+
+import "sync"
+
+var (
+	compiledMu sync.Mutex
+	compiled   []func(tr *Translator)
+)
+
+// RegisterCompiled adds the function that sets compiled translations into a
+// Translator. The code generated by the repository compiler calls it at init,
+// and Translator.LoadCompiled calls the registered functions.
+func RegisterCompiled(register func(tr *Translator)) {
+	compiledMu.Lock()
+	defer compiledMu.Unlock()
+	compiled = append(compiled, register)
+}
+
+// LoadCompiled sets the translations of the RegisterCompiled functions. The
+// translators do not load them unless it is called.
+func (t *Translator) LoadCompiled() {
+	compiledMu.Lock()
+	registers := compiled
+	compiledMu.Unlock()
+
+	for _, register := range registers {
+		register(t)
+	}
+}
@@ -0,0 +1,229 @@
+package i18nmod
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nicksnyder/go-i18n/i18n/language"
+)
+
+// Coverage is the translation coverage of a group locale compared with the
+// reference locale.
+type Coverage struct {
+	Group     string `json:"group"`
+	Locale    string `json:"locale"`
+	Reference string `json:"reference"`
+	// Total is the number of keys of the reference locale.
+	Total int `json:"total"`
+	// Translated is the number of keys of the reference locale found in the
+	// locale.
+	Translated int `json:"translated"`
+	// Missing are the keys of the reference locale not found in the locale.
+	Missing []string `json:"missing"`
+	// Extra are the keys of the locale not found in the reference locale.
+	Extra []string `json:"extra"`
+	// Identical are the keys with the same value of the reference locale.
+	Identical []string `json:"identical"`
+	// PluralForms are the plurals whose cases do not match the CLDR
+	// categories of the locale.
+	PluralForms []*PluralFormsIssue `json:"plural_forms"`
+}
+
+// Percent returns the percent of translated keys.
+func (c *Coverage) Percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return float64(c.Translated) * 100 / float64(c.Total)
+}
+
+// PluralFormsIssue are the categories of a plural missing or not used by the
+// locale.
+type PluralFormsIssue struct {
+	Key     string   `json:"key"`
+	Ordinal bool     `json:"ordinal,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+	Extra   []string `json:"extra,omitempty"`
+}
+
+// Coverage compares the loaded locales of each group with the reference
+// locale. The results are sorted by group and locale.
+func (t *Translator) Coverage(reference string) (result []*Coverage) {
+	t.RLock()
+	defer t.RUnlock()
+
+	locales := map[string]bool{}
+	for _, groupLocales := range t.Groups {
+		for locale := range groupLocales {
+			if locale != AnyLang && locale != reference {
+				locales[locale] = true
+			}
+		}
+	}
+
+	for group, groupLocales := range t.Groups {
+		for locale := range locales {
+			result = append(result, CompareDB(group, locale, reference, groupLocales[locale], groupLocales[reference]))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Locale < result[j].Locale
+	})
+	return
+}
+
+// CompareDB returns the Coverage of db of locale compared with the ref DB of
+// the reference locale.
+func CompareDB(group, locale, reference string, db, ref DB) *Coverage {
+	c := &Coverage{
+		Group:       group,
+		Locale:      locale,
+		Reference:   reference,
+		Total:       len(ref),
+		Missing:     []string{},
+		Extra:       []string{},
+		Identical:   []string{},
+		PluralForms: []*PluralFormsIssue{},
+	}
+
+	for key, rt := range ref {
+		lt, ok := db[key]
+		if !ok {
+			c.Missing = append(c.Missing, key)
+			continue
+		}
+		c.Translated++
+		if lt.Value != "" && lt.Value == rt.Value {
+			c.Identical = append(c.Identical, key)
+		}
+	}
+
+	for key, lt := range db {
+		if _, ok := ref[key]; !ok {
+			c.Extra = append(c.Extra, key)
+		}
+		if issue := pluralFormsIssue(key, locale, lt.Plural, false); issue != nil {
+			c.PluralForms = append(c.PluralForms, issue)
+		}
+		if issue := pluralFormsIssue(key, locale, lt.Ordinal, true); issue != nil {
+			c.PluralForms = append(c.PluralForms, issue)
+		}
+	}
+
+	sort.Strings(c.Missing)
+	sort.Strings(c.Extra)
+	sort.Strings(c.Identical)
+	sort.Slice(c.PluralForms, func(i, j int) bool {
+		a, b := c.PluralForms[i], c.PluralForms[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return !a.Ordinal && b.Ordinal
+	})
+	return c
+}
+
+var pluralCategories = []language.Plural{language.Zero, language.One, language.Two, language.Few, language.Many, language.Other}
+
+// PluralCategories returns the CLDR plural categories used by locale, or nil
+// if the locale rules are unknown.
+func PluralCategories(locale string, ordinal bool) map[language.Plural]bool {
+	categories := map[language.Plural]bool{}
+	if ordinal {
+		// the ordinal rules are functions, so their categories are found by
+		// the results of the first numbers
+		for n := int64(0); n < 1000; n++ {
+			categories[OrdinalCategory(locale, n)] = true
+		}
+		return categories
+	}
+	spec := language.GetPluralSpec(locale)
+	if spec == nil {
+		return nil
+	}
+	for category := range spec.Plurals {
+		categories[category] = true
+	}
+	return categories
+}
+
+// pluralFormsIssue compares the category cases of p with the CLDR categories
+// of locale. The exact and expression cases are ignored.
+func pluralFormsIssue(key, locale string, p *Plural, ordinal bool) *PluralFormsIssue {
+	if p == nil {
+		return nil
+	}
+	categories := PluralCategories(locale, ordinal)
+	if categories == nil {
+		return nil
+	}
+
+	issue := &PluralFormsIssue{Key: key, Ordinal: ordinal}
+	for _, category := range pluralCategories {
+		_, has := p.Cases[string(category)]
+		switch {
+		case categories[category] && !has:
+			issue.Missing = append(issue.Missing, string(category))
+		case !categories[category] && has:
+			issue.Extra = append(issue.Extra, string(category))
+		}
+	}
+	if issue.Missing == nil && issue.Extra == nil {
+		return nil
+	}
+	return issue
+}
+
+// WriteCoverageTable writes the coverage as a text table. If verbose, the keys
+// of each result are written after the table.
+func WriteCoverageTable(w io.Writer, coverage []*Coverage, verbose bool) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tLOCALE\tTOTAL\tTRANSLATED\t%\tMISSING\tEXTRA\tIDENTICAL\tPLURAL FORMS\t")
+	for _, c := range coverage {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f\t%d\t%d\t%d\t%d\t\n", c.Group, c.Locale, c.Total, c.Translated,
+			c.Percent(), len(c.Missing), len(c.Extra), len(c.Identical), len(c.PluralForms))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !verbose {
+		return nil
+	}
+
+	for _, c := range coverage {
+		if len(c.Missing)+len(c.Extra)+len(c.Identical)+len(c.PluralForms) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s [%s]:\n", c.Group, c.Locale)
+		for _, keys := range []struct {
+			name string
+			keys []string
+		}{{"missing", c.Missing}, {"extra", c.Extra}, {"identical", c.Identical}} {
+			if len(keys.keys) > 0 {
+				fmt.Fprintf(w, "  %s: %s\n", keys.name, strings.Join(keys.keys, ", "))
+			}
+		}
+		for _, p := range c.PluralForms {
+			kind := "plural"
+			if p.Ordinal {
+				kind = "ordinal"
+			}
+			fmt.Fprintf(w, "  %s %s:", kind, p.Key)
+			if len(p.Missing) > 0 {
+				fmt.Fprintf(w, " missing %s", strings.Join(p.Missing, ", "))
+			}
+			if len(p.Extra) > 0 {
+				fmt.Fprintf(w, " extra %s", strings.Join(p.Extra, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package i18nmod_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestCoverage(t *testing.T) {
+	plural := func(cases ...string) *i18nmod.Plural {
+		p := &i18nmod.Plural{}
+		for _, c := range cases {
+			p.AddCase(c, c)
+		}
+		return p
+	}
+
+	tr := i18nmod.NewTranslator()
+	tr.NewGroup("en", "app", func(t *i18nmod.Tree) {
+		t.Add(&i18nmod.Translation{Key: "hello", Value: "Hello"})
+		t.Add(&i18nmod.Translation{Key: "ok", Value: "OK"})
+		t.Add(&i18nmod.Translation{Key: "bye", Value: "Bye"})
+		t.Add(&i18nmod.Translation{Key: "items", Plural: plural("one", "other")})
+	})
+	tr.NewGroup("ru", "app", func(t *i18nmod.Tree) {
+		t.Add(&i18nmod.Translation{Key: "hello", Value: "Привет"})
+		t.Add(&i18nmod.Translation{Key: "ok", Value: "OK"})
+		t.Add(&i18nmod.Translation{Key: "extra", Value: "Extra"})
+		t.Add(&i18nmod.Translation{Key: "items", Plural: plural("one", "two", "other")})
+	})
+
+	coverage := tr.Coverage("en")
+	if len(coverage) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(coverage))
+	}
+	c := coverage[0]
+	if c.Group != "app" || c.Locale != "ru" || c.Total != 4 || c.Translated != 3 || c.Percent() != 75 {
+		t.Errorf("bad coverage: %+v", c)
+	}
+	if !reflect.DeepEqual(c.Missing, []string{"bye"}) || !reflect.DeepEqual(c.Extra, []string{"extra"}) ||
+		!reflect.DeepEqual(c.Identical, []string{"ok"}) {
+		t.Errorf("bad keys: %v %v %v", c.Missing, c.Extra, c.Identical)
+	}
+	expected := []*i18nmod.PluralFormsIssue{{Key: "items", Missing: []string{"few", "many"}, Extra: []string{"two"}}}
+	if !reflect.DeepEqual(c.PluralForms, expected) {
+		t.Errorf("bad plural forms: %+v", c.PluralForms[0])
+	}
+
+	var buf bytes.Buffer
+	if err := i18nmod.WriteCoverageTable(&buf, coverage, true); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "75.0") || !strings.Contains(out, "plural items: missing few, many extra two") {
+		t.Errorf("bad table:\n%s", out)
+	}
+}
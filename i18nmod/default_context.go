@@ -48,23 +48,35 @@ func (c *DefaultContext) AddNotFoundHandler(handler func(handler *Handler, t *T)
 	return c
 }
 
+// WithContext returns a copy of c with ctx, like one with a tenant. The copy
+// has its own cache and handlers.
 func (c DefaultContext) WithContext(ctx context.Context) Context {
 	c.Context = ctx
+	c.cache = map[string]*Result{}
+	c.handler = c.handler.with(&c)
 	return &c
 }
 
 func DefaultContextFactory(t *Translator, translate TranslateFunc, lang string, defaultLocale ...string) Context {
+	if len(defaultLocale) == 0 || defaultLocale[0] == "" || defaultLocale[0] == AnyLang {
+		defaultLocale = append([]string{t.DefaultLocale}, defaultLocale...)
+	}
+
 	var (
-		locales = []string{lang}
+		locales = t.FallbackChain(lang)
+		seen    = map[string]bool{}
 	)
 
-	if len(defaultLocale) > 0 && defaultLocale[0] != "" {
-		if defaultLocale[0] != lang {
-			locales = append(locales, defaultLocale[0])
-		}
-	} else if t.DefaultLocale != "" {
-		if t.DefaultLocale != lang {
-			locales = append(locales, t.DefaultLocale)
+	for _, l := range locales {
+		seen[l] = true
+	}
+
+	for _, dl := range defaultLocale {
+		for _, l := range t.FallbackChain(dl) {
+			if !seen[l] {
+				seen[l] = true
+				locales = append(locales, l)
+			}
 		}
 	}
 
@@ -77,6 +89,7 @@ func DefaultContextFactory(t *Translator, translate TranslateFunc, lang string,
 	}
 
 	c.AddHandler(func(handler *Handler, tl *T) (r *Result) {
+		c := handler.Context.(*DefaultContext)
 		if tl.Key.Cached {
 			if r = c.cache[tl.Key.Key]; r != nil {
 				return
@@ -84,10 +97,16 @@ func DefaultContextFactory(t *Translator, translate TranslateFunc, lang string,
 		}
 		r = translate(handler.Context, tl)
 		if r.Translation == nil {
+			if c.LogFaultEnabled {
+				log.Warningf("translation %q of %v not found", tl.Key.Key, tl.Locales)
+			}
 			for _, h := range c.NotFoundHandlers {
 				h(handler, tl)
 			}
 		} else {
+			if c.LogOkEnabled {
+				log.Debugf("translation %q found in %q", tl.Key.Key, r.Locale)
+			}
 			if tl.Key.Cached {
 				c.cache[tl.Key.Key] = r
 			}
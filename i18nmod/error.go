@@ -145,3 +145,37 @@ func (this ErrDataT) Error() (msg string) {
 func (this ErrDataT) Cause(err error) (errs Errors) {
 	return append(errs, this, err)
 }
+
+// TranslationError is the error of a translation lookup or execution.
+type TranslationError struct {
+	Group, Key, Locale, Source string
+	Err                        error
+}
+
+func (this *TranslationError) Error() string {
+	msg := "Translation [" + this.Group + "." + this.Key + "]"
+	if this.Locale != "" {
+		msg += " of locale '" + this.Locale + "'"
+	}
+	if this.Source != "" {
+		msg += " from '" + this.Source + "'"
+	}
+	return msg + " failed: " + this.Err.Error()
+}
+
+func (this *TranslationError) Unwrap() error {
+	return this.Err
+}
+
+// ErrorMode sets how T.Get renders the translation errors.
+type ErrorMode uint8
+
+const (
+	// ErrorText renders the error as "ERROR: <error>".
+	ErrorText ErrorMode = iota
+	// ErrorPanic panics with the *TranslationError.
+	ErrorPanic
+	// ErrorReport calls Translator.ErrorHandler, or logs the error, and
+	// renders the default value.
+	ErrorReport
+)
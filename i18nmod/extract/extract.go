@@ -0,0 +1,632 @@
+// Package extract lists the translation keys used by Go source code.
+//
+// It finds the keys of the calls to the T, TT and NewT functions, the
+// i18nmod.Err values and the ErrData and ErrDataT literals. The keys may be
+// string constants, concatenations and the group functions AutoGroup,
+// StructGroup, PkgToGroup and FormatGroupName, resolved for the package that
+// calls them.
+package extract
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+// Key is a translation key found in the source code.
+type Key struct {
+	Group     string   `json:"group" yaml:"group"`
+	Key       string   `json:"key" yaml:"key"`
+	Default   string   `json:"default,omitempty" yaml:"default,omitempty"`
+	Locations []string `json:"locations" yaml:"locations"`
+	// Fields are the data fields passed by the Data calls of the key.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// DynamicData is true if some of the Data calls of the key have a value
+	// whose fields are unknown.
+	DynamicData bool `json:"dynamic_data,omitempty" yaml:"dynamic_data,omitempty"`
+}
+
+// FullKey returns the key with its group, as used by T.
+func (k *Key) FullKey() string {
+	if k.Group == "" {
+		return k.Key
+	}
+	return k.Group + "." + k.Key
+}
+
+type Extractor struct {
+	// BaseDir is the base directory of the locations. If empty, they are
+	// absolute.
+	BaseDir    string
+	Tests      bool
+	fset       *token.FileSet
+	keys       map[string]*Key
+	Unresolved []string
+}
+
+func New() *Extractor {
+	return &Extractor{fset: token.NewFileSet(), keys: map[string]*Key{}}
+}
+
+// Keys returns the keys sorted by group and key.
+func (e *Extractor) Keys() (keys []*Key) {
+	for _, k := range e.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Group != keys[j].Group {
+			return keys[i].Group < keys[j].Group
+		}
+		return keys[i].Key < keys[j].Key
+	})
+	return
+}
+
+// Walk extracts the keys of the packages in root and in its sub directories,
+// except the vendor, testdata and hidden ones. The import paths are resolved
+// by ImportPath.
+func (e *Extractor) Walk(root string) error {
+	return e.walk(root, func(dir string) (string, error) {
+		return ImportPath(dir)
+	})
+}
+
+// WalkPackage is Walk with the import path of root. The import paths of the
+// sub directories are relative to it.
+func (e *Extractor) WalkPackage(root, importPath string) error {
+	return e.walk(root, func(dir string) (string, error) {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return "", err
+		}
+		return path.Join(importPath, filepath.ToSlash(rel)), nil
+	})
+}
+
+func (e *Extractor) walk(root string, importPath func(dir string) (string, error)) error {
+	return filepath.Walk(root, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if name := info.Name(); pth != root && (name == "vendor" || name == "testdata" || name[0] == '.' || name[0] == '_') {
+			return filepath.SkipDir
+		}
+		if ok, err := e.hasGoFiles(pth); err != nil || !ok {
+			return err
+		}
+		pkgPath, err := importPath(pth)
+		if err != nil {
+			return err
+		}
+		return e.Package(pth, pkgPath)
+	})
+}
+
+// hasGoFiles returns if dir has Go files extracted by Package.
+func (e *Extractor) hasGoFiles(dir string) (bool, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".go") &&
+			(e.Tests || !strings.HasSuffix(name, "_test.go")) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Dir extracts the keys of the package in dir. Its import path is resolved
+// by ImportPath.
+func (e *Extractor) Dir(dir string) error {
+	importPath, err := ImportPath(dir)
+	if err != nil {
+		return err
+	}
+	return e.Package(dir, importPath)
+}
+
+// Package extracts the keys of the package in dir with the import path.
+func (e *Extractor) Package(dir, importPath string) error {
+	pkgs, err := parser.ParseDir(e.fset, dir, func(info os.FileInfo) bool {
+		return e.Tests || !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		p := &pkgExtractor{e: e, importPath: importPath, values: map[string]ast.Expr{}}
+		if strings.HasSuffix(pkg.Name, "_test") {
+			p.importPath += "_test"
+		}
+		for _, f := range pkg.Files {
+			p.collectValues(f)
+		}
+		for _, f := range pkg.Files {
+			p.file(f)
+		}
+	}
+	return nil
+}
+
+// ImportPath returns the import path of dir, using the module path of the
+// nearest go.mod file, or else the path of dir relative to the src directory
+// of GOPATH.
+func ImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		if f, err := os.Open(filepath.Join(d, "go.mod")); err == nil {
+			defer f.Close()
+			s := bufio.NewScanner(f)
+			for s.Scan() {
+				if line := strings.TrimSpace(s.Text()); strings.HasPrefix(line, "module") {
+					modPath := strings.Trim(strings.TrimSpace(line[6:]), `"`)
+					rel, err := filepath.Rel(d, abs)
+					if err != nil {
+						return "", err
+					}
+					return path.Join(modPath, filepath.ToSlash(rel)), nil
+				}
+			}
+			return "", fmt.Errorf("module path not found in %q", filepath.Join(d, "go.mod"))
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		src, err := filepath.Abs(filepath.Join(gopath, "src"))
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(src, abs); err == nil && rel != "." && rel != ".." &&
+			!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel), nil
+		}
+	}
+	return "", fmt.Errorf("go.mod or GOPATH of %q not found", dir)
+}
+
+func (e *Extractor) location(pos token.Pos) string {
+	p := e.fset.Position(pos)
+	file := p.Filename
+	if e.BaseDir != "" {
+		if rel, err := filepath.Rel(e.BaseDir, file); err == nil {
+			file = rel
+		}
+	}
+	return fmt.Sprintf("%s:%d", file, p.Line)
+}
+
+func (e *Extractor) add(key, defaultValue string, pos token.Pos) *Key {
+	k := i18nmod.NewKey(key, nil)
+	name := k.Name()
+	id := k.GroupName + "\x00" + name
+	ek, ok := e.keys[id]
+	if !ok {
+		ek = &Key{Group: k.GroupName, Key: name}
+		e.keys[id] = ek
+	}
+	if ek.Default == "" {
+		ek.Default = defaultValue
+	}
+	ek.Locations = append(ek.Locations, e.location(pos))
+	return ek
+}
+
+type pkgExtractor struct {
+	e          *Extractor
+	importPath string
+	imports    map[string]string
+	values     map[string]ast.Expr
+	depth      int
+}
+
+// collectValues indexes the package level values, used to resolve the idents
+// declared in other files.
+func (p *pkgExtractor) collectValues(f *ast.File) {
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && (gd.Tok == token.CONST || gd.Tok == token.VAR) {
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i < len(vs.Values) {
+						p.values[name.Name] = vs.Values[i]
+					}
+				}
+			}
+		}
+	}
+}
+
+func (p *pkgExtractor) file(f *ast.File) {
+	p.imports = map[string]string{}
+	for _, imp := range f.Imports {
+		pth, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil {
+			p.imports[imp.Name.Name] = pth
+		} else {
+			p.imports[path.Base(pth)] = pth
+		}
+	}
+
+	tcalls := map[*ast.CallExpr]*Key{}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.ValueSpec:
+			if isErrType(x.Type) {
+				for _, v := range x.Values {
+					p.key(v, "", v.Pos())
+				}
+			}
+		case *ast.CompositeLit:
+			if name := funcName(x.Type); name == "ErrData" || name == "ErrDataT" {
+				p.errData(x)
+			}
+		case *ast.CallExpr:
+			switch funcName(x.Fun) {
+			case "T", "TT":
+				if _, ok := x.Fun.(*ast.SelectorExpr); ok && len(x.Args) == 1 {
+					if k := p.key(x.Args[0], "", x.Pos()); k != nil {
+						tcalls[x] = k
+					}
+				}
+			case "NewT":
+				if len(x.Args) == 2 {
+					if k := p.key(x.Args[1], "", x.Pos()); k != nil {
+						tcalls[x] = k
+					}
+				}
+			case "Err":
+				if len(x.Args) == 1 {
+					p.key(x.Args[0], "", x.Pos())
+				}
+			}
+		}
+		return true
+	})
+
+	// the defaults and data are set by the calls chained to the T calls
+	data := map[*ast.CallExpr]*callData{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		method := funcName(call.Fun)
+		if method != "Default" && method != "Data" {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		for x := sel.X; ; {
+			inner, ok := x.(*ast.CallExpr)
+			if !ok {
+				break
+			}
+			if k, ok := tcalls[inner]; ok {
+				if method == "Default" {
+					if s, ok := p.str(call.Args[0]); ok && k.Default == "" {
+						k.Default = s
+					}
+				} else {
+					d := &callData{}
+					d.fields, d.ok = p.dataFields(call.Args[0])
+					data[inner] = d
+				}
+				break
+			}
+			if sel, ok = inner.Fun.(*ast.SelectorExpr); !ok {
+				break
+			}
+			x = sel.X
+		}
+		return true
+	})
+
+	for call, k := range tcalls {
+		if d, ok := data[call]; ok {
+			k.addFields(d.fields, !d.ok)
+		}
+	}
+}
+
+type callData struct {
+	fields []string
+	ok     bool
+}
+
+func (k *Key) addFields(fields []string, dynamic bool) {
+	if dynamic {
+		k.DynamicData = true
+		return
+	}
+	for _, f := range fields {
+		var has bool
+		for _, cur := range k.Fields {
+			if has = cur == f; has {
+				break
+			}
+		}
+		if !has {
+			k.Fields = append(k.Fields, f)
+		}
+	}
+	sort.Strings(k.Fields)
+}
+
+// dataFields returns the field names of the data value, if it is a map or
+// struct literal.
+func (p *pkgExtractor) dataFields(expr ast.Expr) (fields []string, ok bool) {
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return p.dataFields(x.X)
+	case *ast.UnaryExpr:
+		if x.Op == token.AND {
+			return p.dataFields(x.X)
+		}
+	case *ast.Ident:
+		if x.Name == "nil" && x.Obj == nil {
+			return nil, true
+		}
+		if v, isValue := p.decl(x); isValue {
+			return p.dataFields(v)
+		}
+	case *ast.CompositeLit:
+		switch t := x.Type.(type) {
+		case *ast.MapType:
+			if funcName(t.Key) != "string" {
+				return
+			}
+			for _, elt := range x.Elts {
+				kv, isKV := elt.(*ast.KeyValueExpr)
+				if !isKV {
+					return nil, false
+				}
+				name, isStr := p.str(kv.Key)
+				if !isStr {
+					return nil, false
+				}
+				fields = append(fields, name)
+			}
+			return fields, true
+		case *ast.StructType:
+			return structFields(t)
+		case *ast.Ident:
+			if t.Obj != nil {
+				if spec, isType := t.Obj.Decl.(*ast.TypeSpec); isType {
+					if st, isStruct := spec.Type.(*ast.StructType); isStruct {
+						return structFields(st)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func structFields(t *ast.StructType) (fields []string, ok bool) {
+	for _, f := range t.Fields.List {
+		if len(f.Names) == 0 {
+			// the fields of embedded types are unknown
+			return nil, false
+		}
+		for _, name := range f.Names {
+			fields = append(fields, name.Name)
+		}
+	}
+	return fields, true
+}
+
+func (p *pkgExtractor) errData(lit *ast.CompositeLit) {
+	var group, key, message string
+	var ok = true
+	for _, elt := range lit.Elts {
+		kv, isKV := elt.(*ast.KeyValueExpr)
+		if !isKV {
+			return
+		}
+		field, _ := kv.Key.(*ast.Ident)
+		if field == nil {
+			continue
+		}
+		switch field.Name {
+		case "Group":
+			group, ok = p.str(kv.Value)
+		case "Key":
+			key, ok = p.str(kv.Value)
+		case "Message", "MessageT":
+			message, _ = p.str(kv.Value)
+		}
+		if !ok {
+			p.e.Unresolved = append(p.e.Unresolved, p.e.location(kv.Value.Pos()))
+			return
+		}
+	}
+	if key != "" {
+		// the data is set by WithData
+		p.e.add(group+"."+key, message, lit.Pos()).DynamicData = true
+	}
+}
+
+func (p *pkgExtractor) key(expr ast.Expr, defaultValue string, pos token.Pos) *Key {
+	key, ok := p.str(expr)
+	if !ok {
+		p.e.Unresolved = append(p.e.Unresolved, p.e.location(pos))
+		return nil
+	}
+	if key == "" {
+		return nil
+	}
+	return p.e.add(key, defaultValue, pos)
+}
+
+func isErrType(expr ast.Expr) bool {
+	return expr != nil && funcName(expr) == "Err"
+}
+
+// funcName returns the name of the ident or selector expr.
+func funcName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	}
+	return ""
+}
+
+// str resolves the string value of expr.
+func (p *pkgExtractor) str(expr ast.Expr) (s string, ok bool) {
+	if p.depth > 20 {
+		return
+	}
+	p.depth++
+	defer func() {
+		p.depth--
+	}()
+
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if x.Kind == token.STRING {
+			if s, err := strconv.Unquote(x.Value); err == nil {
+				return s, true
+			}
+		}
+	case *ast.ParenExpr:
+		return p.str(x.X)
+	case *ast.BinaryExpr:
+		if x.Op == token.ADD {
+			if a, ok := p.str(x.X); ok {
+				if b, ok := p.str(x.Y); ok {
+					return a + b, true
+				}
+			}
+		}
+	case *ast.Ident:
+		return p.ident(x)
+	case *ast.CallExpr:
+		return p.call(x)
+	}
+	return
+}
+
+func (p *pkgExtractor) ident(id *ast.Ident) (string, bool) {
+	if v, ok := p.decl(id); ok {
+		return p.str(v)
+	}
+	return "", false
+}
+
+// decl returns the value assigned to the ident in its declaration.
+func (p *pkgExtractor) decl(id *ast.Ident) (ast.Expr, bool) {
+	if id.Obj != nil {
+		switch d := id.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			for i, name := range d.Names {
+				if name.Name == id.Name && i < len(d.Values) {
+					return d.Values[i], true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(d.Lhs) == len(d.Rhs) {
+				for i, lhs := range d.Lhs {
+					if name, ok := lhs.(*ast.Ident); ok && name.Name == id.Name {
+						return d.Rhs[i], true
+					}
+				}
+			}
+		}
+		return nil, false
+	}
+	v, ok := p.values[id.Name]
+	return v, ok
+}
+
+func (p *pkgExtractor) call(call *ast.CallExpr) (string, bool) {
+	args := make([]string, len(call.Args))
+	strArgs := func() bool {
+		for i, arg := range call.Args {
+			var ok bool
+			if args[i], ok = p.str(arg); !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch funcName(call.Fun) {
+	case "AutoGroup":
+		return i18nmod.PkgToGroup(p.importPath), true
+	case "GetCalledDir":
+		return p.importPath, true
+	case "PkgToGroup":
+		if len(args) > 0 && strArgs() {
+			return i18nmod.PkgToGroup(args[0], args[1:]...), true
+		}
+	case "FormatGroupName":
+		if len(args) == 1 && strArgs() {
+			return i18nmod.FormatGroupName(args[0]), true
+		}
+	case "StructGroup":
+		if len(args) == 1 {
+			if pkgPath, name := p.typeName(call.Args[0]); name != "" {
+				return i18nmod.PkgToGroup(pkgPath, name), true
+			}
+		}
+	case "string", "Err":
+		if len(args) == 1 {
+			return p.str(call.Args[0])
+		}
+	}
+	return "", false
+}
+
+// typeName returns the package path and type name of the value expression,
+// like T{}, &T{}, (*T)(nil) and pkg.T{}.
+func (p *pkgExtractor) typeName(expr ast.Expr) (pkgPath, name string) {
+	switch x := expr.(type) {
+	case *ast.UnaryExpr:
+		return p.typeName(x.X)
+	case *ast.ParenExpr:
+		return p.typeName(x.X)
+	case *ast.StarExpr:
+		return p.typeName(x.X)
+	case *ast.CompositeLit:
+		return p.typeName(x.Type)
+	case *ast.CallExpr:
+		return p.typeName(x.Fun)
+	case *ast.Ident:
+		return p.importPath, x.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := x.X.(*ast.Ident); ok {
+			if pth, ok := p.imports[pkg.Name]; ok {
+				return pth, x.Sel.Name
+			}
+		}
+	}
+	return
+}
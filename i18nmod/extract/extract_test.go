@@ -0,0 +1,127 @@
+package extract_test
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod/extract"
+)
+
+const source = `package pkg
+
+import "github.com/moisespsena-go/i18n-modular/i18nmod"
+
+var group = i18nmod.AutoGroup()
+
+const ErrNotFound i18nmod.Err = "example_com:app:pkg.errors.not_found"
+
+type User struct{}
+
+func hello(ctx i18nmod.Context, name string) string {
+	g := i18nmod.StructGroup(&User{})
+	ctx.T(g + ".name").Data(map[string]interface{}{"First": name}).Get()
+	ctx.T(group + ".hello").Default("Hello").Data(name).Get()
+	i18nmod.NewT(ctx, "other.key+").Get()
+	_ = i18nmod.ErrData{Group: group, Key: "invalid", Message: "Invalid"}
+	return ctx.T(name).Get()
+}
+`
+
+func TestExtract(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pkgDir := filepath.Join(dir, "pkg")
+	if err = os.Mkdir(pkgDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(pkgDir, "pkg.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := extract.New()
+	e.BaseDir = dir
+	if err := e.Walk(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var got [][3]string
+	for _, k := range e.Keys() {
+		got = append(got, [3]string{k.Group, k.Key, k.Default})
+	}
+	expected := [][3]string{
+		{"example_com:app:pkg", "errors.not_found", ""},
+		{"example_com:app:pkg", "hello", "Hello"},
+		{"example_com:app:pkg", "invalid", "Invalid"},
+		{"example_com:app:pkg:User", "name", ""},
+		{"other", "key", ""},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if loc := e.Keys()[1].Locations; !reflect.DeepEqual(loc, []string{filepath.Join("pkg", "pkg.go") + ":14"}) {
+		t.Errorf("bad locations: %v", loc)
+	}
+	if len(e.Unresolved) != 1 || e.Unresolved[0] != filepath.Join("pkg", "pkg.go")+":17" {
+		t.Errorf("bad unresolved: %v", e.Unresolved)
+	}
+}
+
+func TestExtractGOPATH(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	gopath := build.Default.GOPATH
+	build.Default.GOPATH = dir
+	defer func() { build.Default.GOPATH = gopath }()
+
+	root := filepath.Join(dir, "src", "example.org", "lib")
+	for name, content := range map[string]string{"docs/README.md": "docs\n", "pkg/pkg.go": source} {
+		pth := filepath.Join(root, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if importPath, err := extract.ImportPath(filepath.Join(root, "pkg")); err != nil || importPath != "example.org/lib/pkg" {
+		t.Errorf("expected %q, got %q, %v", "example.org/lib/pkg", importPath, err)
+	}
+
+	// the directories without Go files are skipped
+	e := extract.New()
+	if err := e.Walk(root); err != nil {
+		t.Fatal(err)
+	}
+	if keys := e.Keys(); len(keys) != 5 || keys[1].FullKey() != "example_org:lib:pkg.hello" {
+		t.Errorf("invalid keys %v", keys)
+	}
+
+	e = extract.New()
+	if err := e.WalkPackage(root, "example.com/other"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := e.Keys(); len(keys) != 5 || keys[1].FullKey() != "example_com:other:pkg.hello" {
+		t.Errorf("invalid keys %v", keys)
+	}
+
+	if _, err := extract.ImportPath(os.TempDir()); err == nil {
+		t.Error("expected error of directory out of GOPATH")
+	}
+}
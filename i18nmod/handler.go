@@ -11,3 +11,11 @@ type Handler struct {
 func (h *Handler) Handle(t *T) *Result {
 	return h.Handler(h, t)
 }
+
+// with returns a copy of the handlers chain with context.
+func (h *Handler) with(context Context) *Handler {
+	if h == nil {
+		return nil
+	}
+	return &Handler{Context: context, Prev: h.Prev.with(context), Handler: h.Handler}
+}
@@ -0,0 +1,155 @@
+// Package http is the middleware that creates the translation Context of each
+// request, with the locale found by an ordered list of sources:
+//
+//	m := i18nhttp.New(tr, i18nhttp.PathPrefix(), i18nhttp.Cookie("lang"), i18nhttp.AcceptLanguage())
+//	http.ListenAndServe(":8080", m.Handler(mux))
+//
+// The handlers read it by FromContext:
+//
+//	ctx := i18nhttp.FromContext(r.Context())
+//	fmt.Fprint(w, ctx.T("messages.hello").Get())
+package http
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx with the translation Context c.
+func NewContext(ctx context.Context, c i18nmod.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the translation Context of ctx, or nil.
+func FromContext(ctx context.Context) i18nmod.Context {
+	c, _ := ctx.Value(contextKey{}).(i18nmod.Context)
+	return c
+}
+
+// Source returns the locales requested by r, by preference.
+type Source func(r *http.Request) []string
+
+// PathPrefix returns the locale of the first path segment, like "/pt-BR/users".
+func PathPrefix() Source {
+	return pathPrefix
+}
+
+func pathPrefix(r *http.Request) []string {
+	if segment := firstSegment(r.URL.Path); segment != "" {
+		return []string{segment}
+	}
+	return nil
+}
+
+// isPathPrefix returns whether source is the PathPrefix one. The functions are
+// not comparable, so their code is.
+func isPathPrefix(source Source) bool {
+	return source != nil && reflect.ValueOf(source).Pointer() == reflect.ValueOf(pathPrefix).Pointer()
+}
+
+// Query returns the locale of the query parameter name.
+func Query(name string) Source {
+	return func(r *http.Request) []string {
+		if value := r.URL.Query().Get(name); value != "" {
+			return []string{value}
+		}
+		return nil
+	}
+}
+
+// Cookie returns the locale of the cookie name.
+func Cookie(name string) Source {
+	return func(r *http.Request) []string {
+		if c, err := r.Cookie(name); err == nil && c.Value != "" {
+			return []string{c.Value}
+		}
+		return nil
+	}
+}
+
+// Func returns the locale returned by f, like the one of the user settings.
+func Func(f func(r *http.Request) string) Source {
+	return func(r *http.Request) []string {
+		if locale := f(r); locale != "" {
+			return []string{locale}
+		}
+		return nil
+	}
+}
+
+// AcceptLanguage returns the locales of the Accept-Language header, by
+// q-value. See i18nmod.ParseAcceptLanguage.
+func AcceptLanguage() Source {
+	return func(r *http.Request) []string {
+		return i18nmod.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
+}
+
+func firstSegment(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	if pos := strings.IndexByte(path, '/'); pos != -1 {
+		path = path[0:pos]
+	}
+	return path
+}
+
+// Middleware creates the translation Context of the requests.
+type Middleware struct {
+	Translator *i18nmod.Translator
+	// Sources are tried in order. The first locales matched by
+	// Translator.MatchLocales are used, or the DefaultLocale.
+	Sources []Source
+	// StripPrefix removes the locale path prefix of the requests, so
+	// "/pt-BR/users" is handled as "/users". It requires the PathPrefix source,
+	// and only the prefixes of an exact or high confidence match are removed.
+	StripPrefix bool
+}
+
+// New new middleware of the translator with the sources
+func New(tr *i18nmod.Translator, sources ...Source) *Middleware {
+	return &Middleware{Translator: tr, Sources: sources}
+}
+
+// Locale returns the locale of the request.
+func (m *Middleware) Locale(r *http.Request) string {
+	for _, source := range m.Sources {
+		if locale, confidence := m.Translator.MatchLocales(source(r)...); confidence != i18nmod.ConfidenceNo {
+			return locale
+		}
+	}
+	return m.Translator.DefaultLocale
+}
+
+func (m *Middleware) hasPathPrefix() bool {
+	for _, source := range m.Sources {
+		if isPathPrefix(source) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler returns the handler that calls next with the translation Context in
+// the request context.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := m.Locale(r)
+		if segment := firstSegment(r.URL.Path); m.StripPrefix && segment != "" && m.hasPathPrefix() {
+			if _, confidence := m.Translator.MatchLocales(segment); confidence >= i18nmod.ConfidenceHigh {
+				r = r.Clone(r.Context())
+				if r.URL.Path = strings.TrimPrefix(r.URL.Path, "/"+segment); r.URL.Path == "" {
+					r.URL.Path = "/"
+				}
+				r.URL.RawPath = ""
+			}
+		}
+		c := m.Translator.NewContext(locale).WithContext(r.Context())
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), c)))
+	})
+}
@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	i18nhttp "github.com/moisespsena-go/i18n-modular/i18nmod/http"
+)
+
+func TestMiddleware(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.Locales = []string{"en", "pt-BR", "es"}
+	tr.DefaultLocale = "en"
+	tr.NewGroup("en", "messages", func(tree *i18nmod.Tree) {
+		tree.Add(&i18nmod.Translation{Key: "hello", Value: "Hello"})
+	})
+	tr.NewGroup("pt-BR", "messages", func(tree *i18nmod.Tree) {
+		tree.Add(&i18nmod.Translation{Key: "hello", Value: "Olá"})
+	})
+
+	m := i18nhttp.New(tr,
+		i18nhttp.PathPrefix(),
+		i18nhttp.Query("lang"),
+		i18nhttp.Cookie("lang"),
+		i18nhttp.Func(func(r *http.Request) string { return r.Header.Get("X-User-Locale") }),
+		i18nhttp.AcceptLanguage(),
+	)
+	m.StripPrefix = true
+
+	var path, locale, hello string
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := i18nhttp.FromContext(r.Context())
+		path, locale, hello = r.URL.Path, ctx.Locales()[0], ctx.T("messages.hello").Get()
+	}))
+
+	for _, c := range []struct {
+		target  string
+		headers map[string]string
+		path    string
+		locale  string
+	}{
+		{"/pt-br/users", nil, "/users", "pt-BR"},
+		{"/pt_BR", nil, "/", "pt-BR"},
+		{"/pt-PT/users", nil, "/pt-PT/users", "pt-BR"},
+		{"/id/42", nil, "/id/42", "en"},
+		{"/users?lang=es", map[string]string{"Accept-Language": "pt-BR"}, "/users", "es"},
+		{"/users?lang=xx", map[string]string{"Cookie": "lang=pt-BR"}, "/users", "pt-BR"},
+		{"/users", map[string]string{"X-User-Locale": "es-MX", "Accept-Language": "pt-BR"}, "/users", "es"},
+		{"/users", map[string]string{"Accept-Language": "fr;q=0.9, pt-PT;q=0.5, en;q=0.7"}, "/users", "en"},
+		{"/users", map[string]string{"Accept-Language": "fr"}, "/users", "en"},
+	} {
+		r := httptest.NewRequest("GET", c.target, nil)
+		for name, value := range c.headers {
+			r.Header.Set(name, value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		if path != c.path || locale != c.locale {
+			t.Errorf("%s: expected %s %s, got %s %s", c.target, c.path, c.locale, path, locale)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/pt-BR/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if hello != "Olá" {
+		t.Errorf("expected %q, got %q", "Olá", hello)
+	}
+	if i18nhttp.FromContext(r.Context()) != nil {
+		t.Error("unexpected context of the original request")
+	}
+}
+
+func TestStripPrefix(t *testing.T) {
+	// without Locales, the loaded locales are matched
+	tr := i18nmod.NewTranslator()
+	tr.DefaultLocale = "en"
+	tr.NewGroup("en", "messages", func(tree *i18nmod.Tree) {})
+	tr.NewGroup("pt-BR", "messages", func(tree *i18nmod.Tree) {})
+
+	var path string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	})
+	for _, c := range []struct {
+		sources []i18nhttp.Source
+		target  string
+		path    string
+	}{
+		{[]i18nhttp.Source{i18nhttp.PathPrefix()}, "/pt-BR/users", "/users"},
+		{[]i18nhttp.Source{i18nhttp.PathPrefix()}, "/id/42", "/id/42"},
+		{[]i18nhttp.Source{i18nhttp.PathPrefix()}, "/my/account", "/my/account"},
+		{[]i18nhttp.Source{i18nhttp.Query("lang")}, "/pt-BR/users", "/pt-BR/users"},
+	} {
+		m := i18nhttp.New(tr, c.sources...)
+		m.StripPrefix = true
+		m.Handler(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", c.target, nil))
+		if path != c.path {
+			t.Errorf("%s: expected %s, got %s", c.target, c.path, path)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package i18nmod
 
-import "github.com/moisespsena/template/funcs"
+import (
+	"github.com/moisespsena-go/logging"
+	path_helpers "github.com/moisespsena-go/path-helpers"
+	"github.com/moisespsena/template/funcs"
+)
+
+var log = logging.GetOrCreateLogger(path_helpers.GetCalledDir())
 
 type Group struct {
 	Name  string
@@ -1,3 +1,53 @@
 package i18nmod
 
+import "strings"
+
 const AnyLang = "_"
+
+// ParentLocale returns the locale tag without its last subtag, or an empty
+// string for a tag without subtags. An extension singleton left at the end is
+// removed too: "en-US-u-ca" gives "en-US".
+func ParentLocale(locale string) string {
+	pos := strings.LastIndexAny(locale, "-_")
+	if pos == -1 {
+		return ""
+	}
+	locale = locale[0:pos]
+	if pos = strings.LastIndexAny(locale, "-_"); pos != -1 && len(locale)-pos == 2 {
+		locale = locale[0:pos]
+	}
+	return locale
+}
+
+// AddFallback sets the locales tried after locale, before its parent locale.
+func (t *Translator) AddFallback(locale string, fallbacks ...string) *Translator {
+	t.Lock()
+	defer t.Unlock()
+	if t.Fallbacks == nil {
+		t.Fallbacks = map[string][]string{}
+	}
+	t.Fallbacks[locale] = append(t.Fallbacks[locale], fallbacks...)
+	return t
+}
+
+// FallbackChain returns locale followed by the locales to try when a key is
+// not found on it: the Fallbacks of each locale and then its parent locale.
+// So "es-419" with fallback "es-MX" gives "es-419", "es-MX", "es".
+func (t *Translator) FallbackChain(locale string) (chain []string) {
+	t.RLock()
+	defer t.RUnlock()
+
+	seen := map[string]bool{}
+	var add func(locale string)
+	add = func(locale string) {
+		for ; locale != "" && !seen[locale]; locale = ParentLocale(locale) {
+			seen[locale] = true
+			chain = append(chain, locale)
+			for _, fallback := range t.Fallbacks[locale] {
+				add(fallback)
+			}
+		}
+	}
+	add(locale)
+	return
+}
@@ -0,0 +1,40 @@
+package i18nmod_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestFallbackChain(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.AddFallback("es-419", "es-MX")
+
+	for locale, expected := range map[string][]string{
+		"pt-BR":      {"pt-BR", "pt"},
+		"zh-Hant-TW": {"zh-Hant-TW", "zh-Hant", "zh"},
+		"es-419":     {"es-419", "es-MX", "es"},
+		"en-US-u-ca": {"en-US-u-ca", "en-US", "en"},
+		"en":         {"en"},
+	} {
+		if chain := tr.FallbackChain(locale); !reflect.DeepEqual(chain, expected) {
+			t.Errorf("%s: expected %v, got %v", locale, expected, chain)
+		}
+	}
+}
+
+func TestContextLocales(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.DefaultLocale = "en-US"
+
+	expected := []string{"pt-PT", "pt", "en-US", "en", i18nmod.AnyLang}
+	if locales := tr.NewContext("pt-PT").Locales(); !reflect.DeepEqual(locales, expected) {
+		t.Errorf("expected %v, got %v", expected, locales)
+	}
+
+	expected = []string{"pt-PT", "pt", "es", i18nmod.AnyLang}
+	if locales := tr.NewContext("pt-PT", "es").Locales(); !reflect.DeepEqual(locales, expected) {
+		t.Errorf("expected %v, got %v", expected, locales)
+	}
+}
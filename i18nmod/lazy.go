@@ -0,0 +1,66 @@
+package i18nmod
+
+import "sync"
+
+type groupLoader struct {
+	once sync.Once
+	err  error
+}
+
+// loader returns the load state of the group locale.
+func (t *Translator) loader(lang, group string) *groupLoader {
+	key := group + "[" + lang + "]"
+
+	t.RLock()
+	l, ok := t.loaders[key]
+	t.RUnlock()
+	if ok {
+		return l
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if l, ok = t.loaders[key]; !ok {
+		if t.loaders == nil {
+			t.loaders = map[string]*groupLoader{}
+		}
+		l = &groupLoader{}
+		t.loaders[key] = l
+	}
+	return l
+}
+
+// setLoaded replaces the load state of the group locale by a loaded one, so a
+// failed lazy load is not reported again.
+func (t *Translator) setLoaded(lang, group string) {
+	l := &groupLoader{}
+	l.once.Do(func() {})
+
+	t.Lock()
+	defer t.Unlock()
+	if t.loaders == nil {
+		t.loaders = map[string]*groupLoader{}
+	}
+	t.loaders[group+"["+lang+"]"] = l
+}
+
+// lazyLoad loads the group locale from the backends if it was not loaded yet.
+// Concurrent calls wait for the first one. If it fails, the concurrent calls
+// get its error and the next call loads again.
+func (t *Translator) lazyLoad(lang, group string) error {
+	l := t.loader(lang, group)
+	l.once.Do(func() {
+		var items DB
+		if items, l.err = t.LoadGroupTranslations(lang, group); l.err == nil {
+			t.mergeGroup(group, lang, items)
+			return
+		}
+		key := group + "[" + lang + "]"
+		t.Lock()
+		if t.loaders[key] == l {
+			delete(t.loaders, key)
+		}
+		t.Unlock()
+	})
+	return l.err
+}
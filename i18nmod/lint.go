@@ -0,0 +1,401 @@
+package i18nmod
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+type LintKind string
+
+const (
+	// LintBrokenAlias is an alias ("@" suffix) to a key that does not exist.
+	LintBrokenAlias LintKind = "broken-alias"
+	// LintAliasCycle is an alias chain that returns to a key already followed,
+	// or that is longer than FOLLOW.
+	LintAliasCycle LintKind = "alias-cycle"
+	// LintEmptyLink is a link ("&" suffix) to a node without translations,
+	// created by the link itself.
+	LintEmptyLink LintKind = "empty-link"
+	// LintTemplateField is a template ("~" suffix), a template case or an ICU
+	// message ("!" suffix) that references a data field that the callers do
+	// not pass.
+	LintTemplateField LintKind = "template-field"
+	// LintPluralOther is a plural, ordinal or select without the "other" case.
+	LintPluralOther LintKind = "plural-other"
+)
+
+// LintIssue is a problem of a translation.
+type LintIssue struct {
+	Kind    LintKind `json:"kind"`
+	Group   string   `json:"group"`
+	Key     string   `json:"key"`
+	Locale  string   `json:"locale"`
+	Source  string   `json:"source,omitempty"`
+	Message string   `json:"message"`
+}
+
+func (i *LintIssue) Error() string {
+	return fmt.Sprintf("%s: [%s.%s] of locale '%s': %s (%s)", i.Source, i.Group, i.Key, i.Locale, i.Message, i.Kind)
+}
+
+// Linter checks the translations of the Translator backends.
+type Linter struct {
+	Translator *Translator
+	// Fields are the data fields passed by the callers of the keys, indexed
+	// by "group.key". Only the templates of these keys are checked.
+	Fields map[string][]string
+
+	trees  map[string]map[string]*Tree
+	groups map[string]map[string]DB
+}
+
+func NewLinter(tr *Translator) *Linter {
+	return &Linter{Translator: tr}
+}
+
+// Lint loads the groups of all backends and locales and returns their issues,
+// sorted by group, key and locale.
+func (l *Linter) Lint() (issues []*LintIssue, err error) {
+	l.trees = map[string]map[string]*Tree{}
+	l.groups = map[string]map[string]DB{}
+
+	groups, locales := map[string]bool{}, map[string]bool{}
+	for _, backend := range l.Translator.backends() {
+		for _, group := range backend.ListGroups() {
+			groups[group] = true
+		}
+		for _, locale := range backend.ListLanguages() {
+			locales[locale] = true
+		}
+	}
+
+	for group := range groups {
+		l.trees[group] = map[string]*Tree{}
+		l.groups[group] = map[string]DB{}
+		for locale := range locales {
+			tree, err := l.Translator.LoadGroupTree(locale, group)
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, l.LintTree(group, locale, tree)...)
+			l.trees[group][locale] = tree
+			l.groups[group][locale] = treeDB(locale, group, tree)
+		}
+	}
+
+	for group, locales := range l.groups {
+		for locale, db := range locales {
+			for key, t := range db {
+				if t.Alias != "" {
+					if issue := l.lintAlias(group, key, locale, t); issue != nil {
+						issues = append(issues, issue)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		return a.Kind < b.Kind
+	})
+	return
+}
+
+// LintTree returns the issues of the tree that do not depend on other groups:
+// the empty links, the cases without "other" and the template fields.
+func (l *Linter) LintTree(group, locale string, tree *Tree) (issues []*LintIssue) {
+	issue := func(kind LintKind, key string, source *string, format string, args ...interface{}) {
+		i := &LintIssue{Kind: kind, Group: group, Key: key, Locale: locale, Message: fmt.Sprintf(format, args...)}
+		if source != nil {
+			i.Source = *source
+		}
+		issues = append(issues, i)
+	}
+
+	var walk func(prefix string, t *Tree, path map[*Tree]bool)
+	walk = func(prefix string, t *Tree, path map[*Tree]bool) {
+		if path[t] {
+			return
+		}
+		path[t] = true
+		defer delete(path, t)
+
+		for name, link := range t.Links {
+			if child := t.Children[name]; child != nil && child.T == nil && len(child.Children) == 0 {
+				issue(LintEmptyLink, prefix+name, link.Source, "link to %q has no translations", link.To)
+			}
+		}
+
+		for name, child := range t.Children {
+			if _, ok := t.Links[name]; ok {
+				continue
+			}
+			if tn := child.T; tn != nil {
+				key := prefix + name
+				for _, c := range []struct {
+					name  string
+					value interface{}
+				}{{"plural", tn.Plural}, {"ordinal", tn.Ordinal}, {"select", tn.Select}} {
+					if path := casesWithoutOther(c.value, ""); path != nil {
+						issue(LintPluralOther, key, tn.Source, "%s%s without the 'other' case", c.name, strings.Join(path, ""))
+					}
+				}
+				if fields, ok := l.Fields[group+"."+key]; ok {
+					for _, field := range missingFields(templateTexts(tn), fields) {
+						issue(LintTemplateField, key, tn.Source, "template references the field %q not passed by the callers", field)
+					}
+					if tn.Message != nil {
+						for _, field := range missingArgs(tn.Message, fields) {
+							issue(LintTemplateField, key, tn.Source, "message references the argument %q not passed by the callers", field)
+						}
+					}
+				}
+			}
+			walk(prefix+name+".", child, path)
+		}
+	}
+	walk("", tree, map[*Tree]bool{})
+	return
+}
+
+// casesWithoutOther returns the path of the first plural or select cases,
+// of value or its nested ones, without the "other" case.
+func casesWithoutOther(value interface{}, name string) []string {
+	var cases map[interface{}]interface{}
+	switch v := value.(type) {
+	case *Plural:
+		if v == nil {
+			return nil
+		}
+		cases = v.Cases
+		if _, ok := cases["other"]; !ok {
+			return []string{name}
+		}
+	case *Select:
+		if v == nil {
+			return nil
+		}
+		if _, ok := v.Cases["other"]; !ok {
+			return []string{name}
+		}
+		for k, v := range v.Cases {
+			if path := casesWithoutOther(v, "."+k); path != nil {
+				return append([]string{name}, path...)
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+	for k, v := range cases {
+		if path := casesWithoutOther(v, fmt.Sprintf(".%v", k)); path != nil {
+			return append([]string{name}, path...)
+		}
+	}
+	return nil
+}
+
+// lintAlias follows the alias of t, as T.Get does, and returns the issue of
+// its first hop or of the whole chain.
+func (l *Linter) lintAlias(group, key, locale string, t *Translation) *LintIssue {
+	issue := &LintIssue{Group: group, Key: key, Locale: locale}
+	if t.Source != nil {
+		issue.Source = *t.Source
+	}
+
+	locales := l.Translator.FallbackChain(locale)
+	if l.Translator.DefaultLocale != "" {
+		locales = append(locales, l.Translator.FallbackChain(l.Translator.DefaultLocale)...)
+	}
+	locales = append(locales, AnyLang)
+
+	seen := map[string]bool{group + "." + key: true}
+	for i := 0; ; i++ {
+		alias := t.Alias
+		if alias[0:1] == "." {
+			alias = group + alias
+		}
+		if seen[alias] {
+			issue.Kind, issue.Message = LintAliasCycle, fmt.Sprintf("alias to %q returns to %q", t.Alias, alias)
+			return issue
+		}
+		seen[alias] = true
+
+		k := NewKey(alias, nil)
+		group = k.GroupName
+		if t = l.get(group, k.Name(), locales); t == nil {
+			if i == 0 {
+				issue.Kind, issue.Message = LintBrokenAlias, fmt.Sprintf("alias to %q not found", alias)
+				return issue
+			}
+			// the broken alias is reported by the key that has it
+			return nil
+		}
+		if t.Alias == "" {
+			return nil
+		}
+		if i+1 >= FOLLOW {
+			issue.Kind, issue.Message = LintAliasCycle, fmt.Sprintf("alias chain longer than FOLLOW (%d)", FOLLOW)
+			return issue
+		}
+	}
+}
+
+func (l *Linter) get(group, key string, locales []string) *Translation {
+	for _, locale := range locales {
+		if t, ok := l.groups[group][locale][key]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// missingFields returns the fields referenced by the template text that are not
+// in fields.
+func missingFields(texts []string, fields []string) (missing []string) {
+	passed := map[string]bool{}
+	for _, f := range fields {
+		passed[f] = true
+	}
+	for _, text := range texts {
+		refs, err := TemplateFields(text)
+		if err != nil {
+			continue
+		}
+		for _, f := range refs {
+			if !passed[f] {
+				passed[f] = true
+				missing = append(missing, f)
+			}
+		}
+	}
+	return
+}
+
+// missingArgs returns the arguments of the message not in fields. The count
+// argument is the count value of the callers.
+func missingArgs(m *Message, fields []string) (missing []string) {
+	passed := map[string]bool{"count": true}
+	for _, f := range fields {
+		passed[f] = true
+	}
+	for _, name := range m.Args() {
+		if !passed[name] {
+			missing = append(missing, name)
+		}
+	}
+	return
+}
+
+// templateTexts returns the template texts of t: its value template and the
+// templates of its plural, ordinal and select cases, nested ones included.
+func templateTexts(t *Translation) (texts []string) {
+	if (t.ValueTemplate != nil || t.Template != nil) && t.Value != "" {
+		texts = append(texts, t.Value)
+	}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case *LazyTemplate:
+			texts = append(texts, v.Text)
+		case *Plural:
+			if v != nil {
+				for _, key := range sortedCases(v.Cases) {
+					walk(v.Cases[key])
+				}
+			}
+		case *Select:
+			if v != nil {
+				keys := make([]string, 0, len(v.Cases))
+				for key := range v.Cases {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					walk(v.Cases[key])
+				}
+			}
+		}
+	}
+	walk(t.Plural)
+	walk(t.Ordinal)
+	walk(t.Select)
+	return
+}
+
+// sortedCases returns the keys of the plural cases, sorted by their text.
+func sortedCases(cases map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(cases))
+	for key := range cases {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+// TemplateFields returns the fields of the dot referenced by the template text,
+// in order of appearance. The fields referenced inside "with" and "range" are
+// not returned, because the dot changes.
+func TemplateFields(text string) (fields []string, err error) {
+	tree := parse.New("")
+	tree.Mode = parse.SkipFuncCheck
+	if _, err = tree.Parse(text, "{{", "}}", map[string]*parse.Tree{}); err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n != nil {
+				for _, n := range n.Nodes {
+					walk(n)
+				}
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n != nil {
+				for _, cmd := range n.Cmds {
+					walk(cmd)
+				}
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			if name := n.Ident[0]; !seen[name] {
+				seen[name] = true
+				fields = append(fields, name)
+			}
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		}
+	}
+	walk(tree.Root)
+	return
+}
@@ -0,0 +1,214 @@
+package i18nmod
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Confidence is the confidence of a locale match.
+type Confidence int
+
+const (
+	// ConfidenceNo is no match: other language.
+	ConfidenceNo Confidence = iota
+	// ConfidenceLow is the same language of other region, like "en-US" for
+	// "en-GB".
+	ConfidenceLow
+	// ConfidenceHigh is a parent locale, or a locale of a close region, like
+	// "en-GB" for "en-AU".
+	ConfidenceHigh
+	// ConfidenceExact is the same locale.
+	ConfidenceExact
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "Low"
+	case ConfidenceHigh:
+		return "High"
+	case ConfidenceExact:
+		return "Exact"
+	}
+	return "No"
+}
+
+// regionGroups are the groups of close regions of the languages, the
+// regions not listed are of the default group "".
+var regionGroups = map[string]map[string]string{
+	"en": {"US": "US", "AS": "US", "GU": "US", "MP": "US", "PR": "US", "UM": "US", "VI": "US"},
+	"es": {"ES": "ES", "EA": "ES", "GQ": "ES", "IC": "ES"},
+	"pt": {"BR": "BR"},
+	"zh": {"CN": "HANS", "HANS": "HANS", "MY": "HANS", "SG": "HANS"},
+}
+
+// defaultRegions are the regions of the languages without region.
+var defaultRegions = map[string]string{
+	"en": "US",
+	"es": "ES",
+	"pt": "BR",
+	"zh": "CN",
+}
+
+// ParseAcceptLanguage returns the tags of the Accept-Language header value, by
+// q-value. The tags of the same q-value keep their order. The "*" and q=0 tags
+// are skipped.
+func ParseAcceptLanguage(header string) (tags []string) {
+	type item struct {
+		tag string
+		q   float64
+	}
+	var items []item
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		it := item{tag: strings.TrimSpace(fields[0]), q: 1}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					q = 0
+				}
+				it.q = q
+			}
+		}
+		if it.tag != "" && it.tag != "*" && it.q > 0 {
+			items = append(items, it)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+	for _, it := range items {
+		tags = append(tags, it.tag)
+	}
+	return
+}
+
+// splitLocale returns the language and the region group of the formatted
+// locale.
+func splitLocale(locale string) (lang, group string) {
+	lang = locale
+	var region string
+	if pos := strings.IndexByte(locale, '-'); pos != -1 {
+		lang, region = locale[0:pos], locale[pos+1:]
+	} else if region = defaultRegions[lang]; region == "" {
+		return
+	}
+	if groups, ok := regionGroups[lang]; ok {
+		return lang, groups[region]
+	}
+	return lang, region
+}
+
+// distance returns the distance of the supported locale to the requested one,
+// both formatted, or -1 for other languages.
+func distance(requested, supported string) int {
+	if requested == supported {
+		return 0
+	}
+	rlang, rgroup := splitLocale(requested)
+	slang, sgroup := splitLocale(supported)
+	switch {
+	case rlang != slang:
+		return -1
+	case rgroup == sgroup:
+		return 1
+	case strings.HasPrefix(requested, supported+"-"):
+		return 2
+	case rgroup == "" || sgroup == "":
+		// the regions of the languages without region groups
+		if _, ok := regionGroups[rlang]; !ok {
+			return 2
+		}
+	}
+	return 3
+}
+
+// Match returns the locale of Locales that best matches the Accept-Language
+// header value, and the confidence of the match. See MatchLocales.
+func (tr *Translator) Match(acceptLanguage string) (locale string, confidence Confidence) {
+	return tr.MatchLocales(ParseAcceptLanguage(acceptLanguage)...)
+}
+
+// MatchLocales returns the locale of Locales that best matches the requested
+// ones, by preference, and the confidence of the match. The locales are
+// formatted like the backend ones, so "pt_br" matches "pt-BR". The first
+// requested locale with a high confidence match is used, or else the first one
+// with a low confidence match. The locale of the closer region is preferred,
+// so "en-AU" matches "en-GB" instead of "en-US".
+//
+// If no locale matches, it returns the DefaultLocale. If Locales is empty, the
+// locales of the backends and of the loaded groups are matched.
+func (tr *Translator) MatchLocales(requested ...string) (locale string, confidence Confidence) {
+	supported, defaultLocale := tr.supportedLocales()
+
+	formatted := make([]string, len(supported))
+	for i, s := range supported {
+		if f, err := FormatLang(s); err == nil {
+			formatted[i] = f
+		}
+	}
+
+	var low string
+	for _, r := range requested {
+		r, err := FormatLang(r)
+		if err != nil || r == AnyLang {
+			continue
+		}
+		best, bestDistance := -1, -1
+		for i, s := range formatted {
+			if s == "" {
+				continue
+			}
+			if d := distance(r, s); d != -1 && (best == -1 || d < bestDistance) {
+				best, bestDistance = i, d
+			}
+		}
+		switch {
+		case best == -1:
+		case bestDistance == 0:
+			return supported[best], ConfidenceExact
+		case bestDistance < 3:
+			return supported[best], ConfidenceHigh
+		case low == "":
+			low = supported[best]
+		}
+	}
+	if low != "" {
+		return low, ConfidenceLow
+	}
+	return defaultLocale, ConfidenceNo
+}
+
+// supportedLocales returns the Locales, or the sorted locales of the backends
+// and of the loaded groups if it is empty, and the DefaultLocale.
+func (tr *Translator) supportedLocales() (locales []string, defaultLocale string) {
+	tr.RLock()
+	locales, defaultLocale = tr.Locales, tr.DefaultLocale
+	seen := map[string]bool{AnyLang: true}
+	if len(locales) == 0 {
+		for _, groupLocales := range tr.Groups {
+			for locale := range groupLocales {
+				seen[locale] = true
+			}
+		}
+	}
+	tr.RUnlock()
+	if len(locales) > 0 {
+		return
+	}
+
+	for _, backend := range tr.backends() {
+		for _, locale := range backend.ListLanguages() {
+			seen[locale] = true
+		}
+	}
+	delete(seen, AnyLang)
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return
+}
@@ -0,0 +1,70 @@
+package i18nmod_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tags := i18nmod.ParseAcceptLanguage("fr;q=0.5, pt-BR, *;q=0.1, de;q=0, en;q=0.8, es;q=0.5")
+	if expected := []string{"pt-BR", "en", "fr", "es"}; !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.Locales = []string{"en-US", "en-GB", "pt-BR", "pt", "es-419", "fr-CA", "zh-HANT"}
+	tr.DefaultLocale = "en-US"
+
+	for header, expected := range map[string]struct {
+		locale     string
+		confidence i18nmod.Confidence
+	}{
+		"pt-BR":                        {"pt-BR", i18nmod.ConfidenceExact},
+		"pt-br;q=0.9":                  {"pt-BR", i18nmod.ConfidenceExact},
+		"pt_BR":                        {"pt-BR", i18nmod.ConfidenceExact},
+		"pt-PT":                        {"pt", i18nmod.ConfidenceHigh},
+		"en-AU":                        {"en-GB", i18nmod.ConfidenceHigh},
+		"en-PR":                        {"en-US", i18nmod.ConfidenceHigh},
+		"en":                           {"en-US", i18nmod.ConfidenceHigh},
+		"es-MX":                        {"es-419", i18nmod.ConfidenceHigh},
+		"zh-TW":                        {"zh-HANT", i18nmod.ConfidenceHigh},
+		"fr":                           {"fr-CA", i18nmod.ConfidenceHigh},
+		"fr-FR":                        {"fr-CA", i18nmod.ConfidenceLow},
+		"es-ES":                        {"es-419", i18nmod.ConfidenceLow},
+		"zh-CN, fr-CH;q=0.5":           {"zh-HANT", i18nmod.ConfidenceLow},
+		"fr-FR, pt-PT;q=0.5":           {"pt", i18nmod.ConfidenceHigh},
+		"de, en-ZA;q=0.8, pt-PT;q=0.9": {"pt", i18nmod.ConfidenceHigh},
+		"de":                           {"en-US", i18nmod.ConfidenceNo},
+		"":                             {"en-US", i18nmod.ConfidenceNo},
+	} {
+		locale, confidence := tr.Match(header)
+		if locale != expected.locale || confidence != expected.confidence {
+			t.Errorf("%q: expected %s %v, got %s %v", header, expected.locale, expected.confidence, locale, confidence)
+		}
+	}
+
+	// without Locales, the locales of the loaded groups are matched
+	tr.Locales = nil
+	if locale, confidence := tr.MatchLocales("xx", "pt_br"); locale != "en-US" || confidence != i18nmod.ConfidenceNo {
+		t.Errorf("expected en-US No, got %s %v", locale, confidence)
+	}
+	tr.NewGroup("pt-BR", "messages", func(tree *i18nmod.Tree) {})
+	tr.NewGroup("en", "messages", func(tree *i18nmod.Tree) {})
+	for requested, expected := range map[string]struct {
+		locale     string
+		confidence i18nmod.Confidence
+	}{
+		"pt_br": {"pt-BR", i18nmod.ConfidenceExact},
+		"pt-PT": {"pt-BR", i18nmod.ConfidenceLow},
+		"en-GB": {"en", i18nmod.ConfidenceHigh},
+		"id":    {"en-US", i18nmod.ConfidenceNo},
+	} {
+		if locale, confidence := tr.MatchLocales("xx", requested); locale != expected.locale || confidence != expected.confidence {
+			t.Errorf("%q: expected %s %v, got %s %v", requested, expected.locale, expected.confidence, locale, confidence)
+		}
+	}
+}
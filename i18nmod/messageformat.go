@@ -0,0 +1,486 @@
+package i18nmod
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Message is a parsed ICU MessageFormat pattern. It supports simple
+// arguments ("{name}", "{name, number}"), plural, selectordinal and select
+// arguments, "#" inside plurals and the apostrophe quoting.
+type Message struct {
+	Pattern string
+	nodes   []msgNode
+}
+
+// ParseMessage parses the ICU MessageFormat pattern.
+func ParseMessage(pattern string) (*Message, error) {
+	p := &msgParser{s: []rune(pattern)}
+	nodes, err := p.parse(false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Parse message %q failed: %v", pattern, err)
+	}
+	return &Message{Pattern: pattern, nodes: nodes}, nil
+}
+
+// MustParseMessage is like ParseMessage but panics if the pattern is invalid.
+// It is used by the compiled translations, validated at build time.
+func MustParseMessage(pattern string) *Message {
+	m, err := ParseMessage(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MessageArgs returns the value of the named argument.
+type MessageArgs func(name string) (value interface{}, ok bool)
+
+// Format formats the message with the plural rules of locale.
+func (m *Message) Format(locale string, args MessageArgs) (string, error) {
+	var b strings.Builder
+	if err := formatNodes(m.nodes, &msgState{locale: locale, args: args}, &b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Args returns the names of the arguments used by the message, in order of
+// appearance.
+func (m *Message) Args() (names []string) {
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	var walk func(nodes []msgNode)
+	walk = func(nodes []msgNode) {
+		for _, n := range nodes {
+			switch n := n.(type) {
+			case *msgArg:
+				add(n.name)
+			case *msgSelect:
+				add(n.name)
+				for _, nodes := range n.cases {
+					walk(nodes)
+				}
+			case *msgPlural:
+				add(n.name)
+				for _, nodes := range n.exact {
+					walk(nodes)
+				}
+				for _, nodes := range n.cases {
+					walk(nodes)
+				}
+			}
+		}
+	}
+	walk(m.nodes)
+	return
+}
+
+type msgState struct {
+	locale string
+	args   MessageArgs
+	pound  []string
+}
+
+type msgNode interface {
+	format(s *msgState, b *strings.Builder) error
+}
+
+func formatNodes(nodes []msgNode, s *msgState, b *strings.Builder) error {
+	for _, n := range nodes {
+		if err := n.format(s, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type msgText string
+
+func (n msgText) format(s *msgState, b *strings.Builder) error {
+	b.WriteString(string(n))
+	return nil
+}
+
+type msgPound struct{}
+
+func (msgPound) format(s *msgState, b *strings.Builder) error {
+	if len(s.pound) > 0 {
+		b.WriteString(s.pound[len(s.pound)-1])
+	} else {
+		b.WriteByte('#')
+	}
+	return nil
+}
+
+type msgArg struct {
+	name string
+}
+
+func (n *msgArg) format(s *msgState, b *strings.Builder) error {
+	v, err := s.arg(n.name)
+	if err != nil {
+		return err
+	}
+	b.WriteString(fmt.Sprint(v))
+	return nil
+}
+
+func (s *msgState) arg(name string) (interface{}, error) {
+	if s.args != nil {
+		if v, ok := s.args(name); ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("missing argument %q", name)
+}
+
+type msgSelect struct {
+	name  string
+	cases map[string][]msgNode
+}
+
+func (n *msgSelect) format(s *msgState, b *strings.Builder) error {
+	v, err := s.arg(n.name)
+	if err != nil {
+		return err
+	}
+	nodes, ok := n.cases[fmt.Sprint(v)]
+	if !ok {
+		nodes = n.cases["other"]
+	}
+	return formatNodes(nodes, s, b)
+}
+
+type msgPlural struct {
+	name    string
+	ordinal bool
+	offset  float64
+	exact   map[float64][]msgNode
+	cases   map[string][]msgNode
+}
+
+func (n *msgPlural) format(s *msgState, b *strings.Builder) error {
+	v, err := s.arg(n.name)
+	if err != nil {
+		return err
+	}
+
+	f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+	if err != nil {
+		return fmt.Errorf("argument %q: %v is not a number", n.name, v)
+	}
+
+	nodes, ok := n.exact[f]
+	if n.offset != 0 {
+		v = f - n.offset
+	}
+	if !ok {
+		p := &Plural{Locale: s.locale, Ordinal: n.ordinal}
+		if nodes, ok = n.cases[string(p.Category(v))]; !ok {
+			nodes = n.cases["other"]
+		}
+	}
+
+	if n.offset != 0 {
+		s.pound = append(s.pound, strconv.FormatFloat(f-n.offset, 'f', -1, 64))
+	} else {
+		s.pound = append(s.pound, fmt.Sprint(v))
+	}
+	defer func() {
+		s.pound = s.pound[0 : len(s.pound)-1]
+	}()
+	return formatNodes(nodes, s, b)
+}
+
+type msgParser struct {
+	s   []rune
+	pos int
+}
+
+func (p *msgParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *msgParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// parse parses the message until the end of input, or until the "}" that
+// closes it if depth > 0. inPlural enables the "#" replacement.
+func (p *msgParser) parse(inPlural bool, depth int) (nodes []msgNode, err error) {
+	var text []rune
+	flush := func() {
+		if len(text) > 0 {
+			nodes = append(nodes, msgText(text))
+			text = nil
+		}
+	}
+
+	for !p.eof() {
+		c := p.s[p.pos]
+		switch {
+		case c == '\'':
+			p.pos++
+			if p.eof() {
+				text = append(text, c)
+			} else if p.s[p.pos] == '\'' {
+				text = append(text, c)
+				p.pos++
+			} else if strings.ContainsRune("{}#|", p.s[p.pos]) {
+				for !p.eof() {
+					if p.s[p.pos] == '\'' {
+						if p.pos+1 < len(p.s) && p.s[p.pos+1] == '\'' {
+							text = append(text, '\'')
+							p.pos += 2
+							continue
+						}
+						p.pos++
+						break
+					}
+					text = append(text, p.s[p.pos])
+					p.pos++
+				}
+			} else {
+				text = append(text, c)
+			}
+		case c == '{':
+			flush()
+			p.pos++
+			var node msgNode
+			if node, err = p.parseArgument(inPlural, depth+1); err != nil {
+				return
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unexpected '}' at %d", p.pos)
+			}
+			p.pos++
+			flush()
+			return
+		case c == '#' && inPlural:
+			flush()
+			p.pos++
+			nodes = append(nodes, msgPound{})
+		default:
+			text = append(text, c)
+			p.pos++
+		}
+	}
+
+	if depth > 0 {
+		return nil, fmt.Errorf("unclosed '{'")
+	}
+	flush()
+	return
+}
+
+func (p *msgParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for !p.eof() {
+		c := p.s[p.pos]
+		if unicode.IsSpace(c) || c == ',' || c == '{' || c == '}' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.s[start:p.pos])
+}
+
+func (p *msgParser) expect(c rune) error {
+	p.skipSpaces()
+	if p.eof() {
+		return fmt.Errorf("expected '%c' at end", c)
+	}
+	if p.s[p.pos] != c {
+		return fmt.Errorf("expected '%c' at %d, found '%c'", c, p.pos, p.s[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+func (p *msgParser) parseArgument(inPlural bool, depth int) (msgNode, error) {
+	name := p.word()
+	if name == "" {
+		return nil, fmt.Errorf("argument name expected at %d", p.pos)
+	}
+
+	p.skipSpaces()
+	if !p.eof() && p.s[p.pos] == '}' {
+		p.pos++
+		return &msgArg{name}, nil
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+
+	typ := p.word()
+	p.skipSpaces()
+	if !p.eof() && p.s[p.pos] == '}' {
+		p.pos++
+		return &msgArg{name}, nil
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case "plural", "selectordinal":
+		return p.parsePlural(name, typ == "selectordinal", depth)
+	case "select":
+		cases, err := p.parseCases(inPlural, depth, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &msgSelect{name, cases}, nil
+	default:
+		// the style of other types, like number or date, is ignored
+		for level := 1; !p.eof(); p.pos++ {
+			switch p.s[p.pos] {
+			case '{':
+				level++
+			case '}':
+				if level--; level == 0 {
+					p.pos++
+					return &msgArg{name}, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("unclosed argument %q", name)
+	}
+}
+
+func (p *msgParser) parsePlural(name string, ordinal bool, depth int) (msgNode, error) {
+	n := &msgPlural{name: name, ordinal: ordinal, exact: map[float64][]msgNode{}}
+	cases, err := p.parseCases(true, depth, func(selector string) (ok bool, err error) {
+		if strings.HasPrefix(selector, "offset:") {
+			n.offset, err = strconv.ParseFloat(selector[7:], 64)
+			return true, err
+		}
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	for selector, nodes := range cases {
+		if selector[0] == '=' {
+			f, err := strconv.ParseFloat(selector[1:], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plural selector %q", selector)
+			}
+			n.exact[f] = nodes
+			delete(cases, selector)
+		}
+	}
+	n.cases = cases
+	return n, nil
+}
+
+// parseCases parses "selector {message}" pairs until the "}" that closes the
+// argument. The "other" case is required.
+func (p *msgParser) parseCases(inPlural bool, depth int, option func(selector string) (bool, error)) (cases map[string][]msgNode, err error) {
+	cases = map[string][]msgNode{}
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, fmt.Errorf("unclosed '{'")
+		}
+		if p.s[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		selector := p.word()
+		if selector == "offset:" {
+			selector += p.word()
+		}
+		if selector == "" {
+			return nil, fmt.Errorf("selector expected at %d", p.pos)
+		}
+		if option != nil {
+			var ok bool
+			if ok, err = option(selector); err != nil {
+				return
+			} else if ok {
+				continue
+			}
+		}
+
+		if err = p.expect('{'); err != nil {
+			return
+		}
+		if cases[selector], err = p.parse(inPlural, depth+1); err != nil {
+			return
+		}
+	}
+
+	if _, ok := cases["other"]; !ok {
+		return nil, fmt.Errorf("the 'other' case is required")
+	}
+	return
+}
+
+// messageArgs returns the MessageArgs of tl: the "count" argument is the
+// CountValue and the others are read from DataValue, that is a map, a struct or
+// a slice (for numbered arguments).
+func messageArgs(tl *T) MessageArgs {
+	return func(name string) (interface{}, bool) {
+		if name == "count" && tl.CountValue != nil {
+			return tl.CountValue, true
+		}
+
+		data := tl.DataValue
+		if tfd, ok := data.(TemplateFuncsData); ok {
+			data = tfd.Data()
+		}
+
+		switch d := data.(type) {
+		case nil:
+			return nil, false
+		case map[string]interface{}:
+			v, ok := d[name]
+			return v, ok
+		case map[string]string:
+			v, ok := d[name]
+			return v, ok
+		case map[interface{}]interface{}:
+			v, ok := d[name]
+			return v, ok
+		}
+
+		rv := reflect.Indirect(reflect.ValueOf(data))
+		switch rv.Kind() {
+		case reflect.Map:
+			if rv.Type().Key().Kind() == reflect.String {
+				if v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key())); v.IsValid() {
+					return v.Interface(), true
+				}
+			}
+		case reflect.Struct:
+			if f := rv.FieldByName(name); f.IsValid() && f.CanInterface() {
+				return f.Interface(), true
+			}
+		case reflect.Slice, reflect.Array:
+			if i, err := strconv.Atoi(name); err == nil && i >= 0 && i < rv.Len() {
+				return rv.Index(i).Interface(), true
+			}
+		}
+		return nil, false
+	}
+}
+
+func (t *Translation) translateMessage(lang string, tl *T, r *Result) {
+	r.value, r.Error = t.Message.Format(lang, messageArgs(tl))
+}
@@ -0,0 +1,47 @@
+package i18nmod_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestMessageFormat(t *testing.T) {
+	for _, c := range []struct {
+		locale, pattern string
+		args            map[string]interface{}
+		expected        string
+	}{
+		{"en", "Hello {name}!", map[string]interface{}{"name": "Ana"}, "Hello Ana!"},
+		{"en", "{count, plural, one {# file} other {# files}}", map[string]interface{}{"count": 1}, "1 file"},
+		{"en", "{count, plural, =0 {no files} one {# file} other {# files}}", map[string]interface{}{"count": 0}, "no files"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", map[string]interface{}{"n": 22}, "22 файла"},
+		{"en", "{pos, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", map[string]interface{}{"pos": 23}, "23rd"},
+		{"en", "{gender, select, female {She has {count, plural, one {# item} other {# items}}} other {They have # items}}",
+			map[string]interface{}{"gender": "female", "count": 2}, "She has 2 items"},
+		{"en", "{guests, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}",
+			map[string]interface{}{"guests": 3, "host": "Ana"}, "Ana and 2 others"},
+		{"en", "It''s '{quoted}' {n, number}", map[string]interface{}{"n": 5}, "It's {quoted} 5"},
+	} {
+		m, err := i18nmod.ParseMessage(c.pattern)
+		if err != nil {
+			t.Errorf("%s: %v", c.pattern, err)
+			continue
+		}
+		got, err := m.Format(c.locale, func(name string) (interface{}, bool) {
+			v, ok := c.args[name]
+			return v, ok
+		})
+		if err != nil {
+			t.Errorf("%s: %v", c.pattern, err)
+		} else if got != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.pattern, c.expected, got)
+		}
+	}
+
+	for _, pattern := range []string{"{n, plural, one {x}}", "{n", "a } b", "{n, select, other {x}"} {
+		if _, err := i18nmod.ParseMessage(pattern); err == nil {
+			t.Errorf("%s: expected error", pattern)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package i18nmod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+var pkgPath = reflect.TypeOf(Translator{}).PkgPath()
+
+// MissingKey is a translation key not found in a locale of a lookup, tried
+// before the locale that has it, if any.
+type MissingKey struct {
+	Group     string    `json:"group"`
+	Key       string    `json:"key"`
+	Locale    string    `json:"locale"`
+	Default   string    `json:"default,omitempty"`
+	Hits      int64     `json:"hits"`
+	Caller    string    `json:"caller,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// MissingCollector aggregates the missing keys of the Translator lookups. It is
+// safe for concurrent use.
+type MissingCollector struct {
+	mu   sync.Mutex
+	keys map[string]*MissingKey
+}
+
+func NewMissingCollector() *MissingCollector {
+	return &MissingCollector{keys: map[string]*MissingKey{}}
+}
+
+// Add records a miss of key of group in locale.
+func (c *MissingCollector) Add(group, key, locale, defaultValue string) {
+	c.add(group, key, locale, defaultValue, caller)
+}
+
+// AddCaller is like Add, but with the given call site.
+func (c *MissingCollector) AddCaller(group, key, locale, defaultValue, callSite string) {
+	c.add(group, key, locale, defaultValue, func() string {
+		return callSite
+	})
+}
+
+func (c *MissingCollector) add(group, key, locale, defaultValue string, caller func() string) {
+	id := group + "\x00" + locale + "\x00" + key
+
+	c.mu.Lock()
+	if m, ok := c.keys[id]; ok {
+		m.Hits++
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+
+	m := &MissingKey{
+		Group:     group,
+		Key:       key,
+		Locale:    locale,
+		Default:   defaultValue,
+		Hits:      1,
+		Caller:    caller(),
+		FirstSeen: time.Now(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cur, ok := c.keys[id]; ok {
+		cur.Hits++
+	} else {
+		c.keys[id] = m
+	}
+}
+
+// caller returns the file:line of the first caller outside of this package.
+func caller() string {
+	pc := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pc[0:runtime.Callers(2, pc)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPath+".") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// Keys returns the missing keys sorted by group, locale and key.
+func (c *MissingCollector) Keys() (keys []MissingKey) {
+	c.mu.Lock()
+	for _, m := range c.keys {
+		keys = append(keys, *m)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		return a.Key < b.Key
+	})
+	return
+}
+
+func (c *MissingCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = map[string]*MissingKey{}
+}
+
+// WriteJSON writes the missing keys as a JSON array.
+func (c *MissingCollector) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	keys := c.Keys()
+	if keys == nil {
+		keys = []MissingKey{}
+	}
+	return enc.Encode(keys)
+}
+
+// YAMLStubs returns, by group and locale, YAML contents with the missing keys
+// and their default values, ready to be translated.
+func (c *MissingCollector) YAMLStubs() (stubs map[string]map[string][]byte, err error) {
+	trees := map[string]map[string]*stubNode{}
+	for _, m := range c.Keys() {
+		if trees[m.Group] == nil {
+			trees[m.Group] = map[string]*stubNode{}
+		}
+		root := trees[m.Group][m.Locale]
+		if root == nil {
+			root = &stubNode{}
+			trees[m.Group][m.Locale] = root
+		}
+		root.add(strings.Split(m.Key, "."), m.Default)
+	}
+
+	stubs = map[string]map[string][]byte{}
+	for group, locales := range trees {
+		stubs[group] = map[string][]byte{}
+		for locale, root := range locales {
+			if stubs[group][locale], err = yaml.Marshal(root.mapSlice()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// WriteYAMLStubs writes the YAMLStubs into dir, using the LoadDir layout:
+// "<group dir>/<locale>.yaml".
+func (c *MissingCollector) WriteYAMLStubs(dir string) error {
+	stubs, err := c.YAMLStubs()
+	if err != nil {
+		return err
+	}
+	for group, locales := range stubs {
+		groupDir := filepath.Join(dir, filepath.FromSlash(strings.Replace(group, ":", "/", -1)))
+		if err = os.MkdirAll(groupDir, 0755); err != nil {
+			return err
+		}
+		for locale, data := range locales {
+			if err = ioutil.WriteFile(filepath.Join(groupDir, locale+".yaml"), data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type stubNode struct {
+	names    []string
+	children map[string]*stubNode
+	value    *string
+}
+
+func (n *stubNode) add(path []string, value string) {
+	if len(path) == 0 {
+		n.value = &value
+		return
+	}
+	if n.children == nil {
+		n.children = map[string]*stubNode{}
+	}
+	child, ok := n.children[path[0]]
+	if !ok {
+		child = &stubNode{}
+		n.children[path[0]] = child
+		n.names = append(n.names, path[0])
+	}
+	child.add(path[1:], value)
+}
+
+// mapSlice returns the children of n as ordered YAML map. A node with value
+// and children keeps only the children, because YAML does not allow both.
+func (n *stubNode) mapSlice() (ms yaml.MapSlice) {
+	for _, name := range n.names {
+		child := n.children[name]
+		if child.children != nil {
+			ms = append(ms, yaml.MapItem{Key: name, Value: child.mapSlice()})
+		} else {
+			ms = append(ms, yaml.MapItem{Key: name, Value: *child.value})
+		}
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package i18nmod_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestMissingCollector(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.Missing = i18nmod.NewMissingCollector()
+	tr.NewGroup("en", "g", func(tree *i18nmod.Tree) {
+		tree.Add(&i18nmod.Translation{Key: "found", Value: "Found"})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := tr.NewContext("en")
+			ctx.T("g.found").Get()
+			ctx.T("g.user.name").Default("User Name").Get()
+			ctx.T("g.user.email").Get()
+		}()
+	}
+	wg.Wait()
+	tr.NewContext("pt").T("g.user.name").Get()
+
+	keys := tr.Missing.Keys()
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 missing keys, got %v", keys)
+	}
+	if k := keys[0]; k.Key != "user.email" || k.Locale != "en" || k.Hits != 10 || !strings.Contains(k.Caller, "missing_test.go:") {
+		t.Errorf("unexpected %#v", k)
+	}
+	if k := keys[1]; k.Key != "user.name" || k.Default != "User Name" || k.Hits != 10 {
+		t.Errorf("unexpected %#v", k)
+	}
+
+	var buf bytes.Buffer
+	if err := tr.Missing.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []i18nmod.MissingKey
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 3 {
+		t.Errorf("invalid JSON %s: %v", buf.String(), err)
+	}
+
+	stubs, err := tr.Missing.YAMLStubs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "user:\n  email: \"\"\n  name: User Name\n"
+	if got := string(stubs["g"]["en"]); got != expected {
+		t.Errorf("expected stub %q, got %q", expected, got)
+	}
+}
+
+func TestMissingFallback(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.Missing = i18nmod.NewMissingCollector()
+	tr.NewGroup("en", "g", func(tree *i18nmod.Tree) {
+		tree.Add(&i18nmod.Translation{Key: "hello", Value: "Hello"})
+	})
+
+	// the key found in the fallback locale is missing in the previous ones
+	if got := tr.NewContext("pt", "en").T("g.hello").Get(); got != "Hello" {
+		t.Errorf("expected %q, got %q", "Hello", got)
+	}
+	keys := tr.Missing.Keys()
+	if len(keys) != 1 || keys[0].Key != "hello" || keys[0].Locale != "pt" {
+		t.Errorf("expected the pt miss, got %v", keys)
+	}
+
+	// the key of the first locale is not missing
+	tr.Missing.Reset()
+	tr.NewContext("en", "pt").T("g.hello").Get()
+	if keys := tr.Missing.Keys(); len(keys) != 0 {
+		t.Errorf("unexpected misses %v", keys)
+	}
+}
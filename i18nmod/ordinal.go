@@ -0,0 +1,207 @@
+package i18nmod
+
+import (
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/i18n/language"
+)
+
+type OrdinalFunc func(n int64) language.Plural
+
+// OrdinalRules are the CLDR ordinal plural rules indexed by language. Languages
+// not listed here use only the "other" category.
+var OrdinalRules = map[string]OrdinalFunc{
+	"en": func(n int64) language.Plural {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return language.One
+		case n%10 == 2 && n%100 != 12:
+			return language.Two
+		case n%10 == 3 && n%100 != 13:
+			return language.Few
+		}
+		return language.Other
+	},
+	"fr":  ordinalOneIf(1),
+	"fil": ordinalOneIf(1),
+	"tl":  ordinalOneIf(1),
+	"ga":  ordinalOneIf(1),
+	"hy":  ordinalOneIf(1),
+	"lo":  ordinalOneIf(1),
+	"ms":  ordinalOneIf(1),
+	"ro":  ordinalOneIf(1),
+	"vi":  ordinalOneIf(1),
+	"hu":  ordinalOneIf(1, 5),
+	"ne":  ordinalOneIf(1, 2, 3, 4),
+	"it": func(n int64) language.Plural {
+		if intEquals(n, 11, 8, 80, 800) {
+			return language.Many
+		}
+		return language.Other
+	},
+	"sv": func(n int64) language.Plural {
+		if intEquals(n%10, 1, 2) && !intEquals(n%100, 11, 12) {
+			return language.One
+		}
+		return language.Other
+	},
+	"ca": func(n int64) language.Plural {
+		switch n {
+		case 1, 3:
+			return language.One
+		case 2:
+			return language.Two
+		case 4:
+			return language.Few
+		}
+		return language.Other
+	},
+	"cy": func(n int64) language.Plural {
+		switch n {
+		case 0, 7, 8, 9:
+			return language.Zero
+		case 1:
+			return language.One
+		case 2:
+			return language.Two
+		case 3, 4:
+			return language.Few
+		case 5, 6:
+			return language.Many
+		}
+		return language.Other
+	},
+	"hi": ordinalHindi,
+	"gu": ordinalHindi,
+	"bn": ordinalBengali,
+	"as": ordinalBengali,
+	"gd": func(n int64) language.Plural {
+		switch n {
+		case 1, 11:
+			return language.One
+		case 2, 12:
+			return language.Two
+		case 3, 13:
+			return language.Few
+		}
+		return language.Other
+	},
+	"ka": func(n int64) language.Plural {
+		switch {
+		case n == 1:
+			return language.One
+		case n == 0 || (n%100 >= 2 && n%100 <= 20) || intEquals(n%100, 40, 60, 80):
+			return language.Many
+		}
+		return language.Other
+	},
+	"kk": func(n int64) language.Plural {
+		if n%10 == 6 || n%10 == 9 || (n%10 == 0 && n != 0) {
+			return language.Many
+		}
+		return language.Other
+	},
+	"mk": func(n int64) language.Plural {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return language.One
+		case n%10 == 2 && n%100 != 12:
+			return language.Two
+		case intEquals(n%10, 7, 8) && !intEquals(n%100, 17, 18):
+			return language.Many
+		}
+		return language.Other
+	},
+	"sq": func(n int64) language.Plural {
+		switch {
+		case n == 1:
+			return language.One
+		case n%10 == 4 && n%100 != 14:
+			return language.Many
+		}
+		return language.Other
+	},
+	"uk": func(n int64) language.Plural {
+		if n%10 == 3 && n%100 != 13 {
+			return language.Few
+		}
+		return language.Other
+	},
+	"be": func(n int64) language.Plural {
+		if intEquals(n%10, 2, 3) && !intEquals(n%100, 12, 13) {
+			return language.Few
+		}
+		return language.Other
+	},
+	"tk": func(n int64) language.Plural {
+		if intEquals(n%10, 6, 9) || n == 10 {
+			return language.Few
+		}
+		return language.Other
+	},
+}
+
+func ordinalOneIf(values ...int64) OrdinalFunc {
+	return func(n int64) language.Plural {
+		if intEquals(n, values...) {
+			return language.One
+		}
+		return language.Other
+	}
+}
+
+func ordinalHindi(n int64) language.Plural {
+	switch n {
+	case 1:
+		return language.One
+	case 2, 3:
+		return language.Two
+	case 4:
+		return language.Few
+	case 6:
+		return language.Many
+	}
+	return language.Other
+}
+
+func ordinalBengali(n int64) language.Plural {
+	switch n {
+	case 1, 5, 7, 8, 9, 10:
+		return language.One
+	case 2, 3:
+		return language.Two
+	case 4:
+		return language.Few
+	case 6:
+		return language.Many
+	}
+	return language.Other
+}
+
+func intEquals(n int64, values ...int64) bool {
+	for _, v := range values {
+		if n == v {
+			return true
+		}
+	}
+	return false
+}
+
+// OrdinalCategory returns the CLDR ordinal plural category of count in locale.
+// Counts that are not integers are "other".
+func OrdinalCategory(locale string, count interface{}) language.Plural {
+	operand, numeric := pluralOperand(count)
+	n, ok := operand.(int64)
+	if !numeric || !ok {
+		return language.Other
+	}
+	if n < 0 {
+		n = -n
+	}
+	for locale = strings.ToLower(locale); locale != ""; locale = ParentLocale(locale) {
+		if rule, ok := OrdinalRules[locale]; ok {
+			return rule(n)
+		}
+	}
+	return language.Other
+}
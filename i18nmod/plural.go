@@ -2,6 +2,10 @@ package i18nmod
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/nicksnyder/go-i18n/i18n/language"
 )
 
 type PluralKeyCount struct {
@@ -32,6 +36,12 @@ func (k PluralKeyCount) Accept(value interface{}) bool {
 type Plural struct {
 	Cases    map[interface{}]interface{}
 	ExpCases map[PluralKeyCount]interface{}
+	// Locale selects the CLDR plural rules used by Find. It is set by the
+	// Translator when the translation is loaded.
+	Locale string
+	// Ordinal selects the ordinal rules (1st, 2nd, 3rd) instead of the
+	// cardinal ones.
+	Ordinal bool
 }
 
 func (p *Plural) AddCase(key, value interface{}) {
@@ -111,11 +121,24 @@ func (p *Plural) MustFind(count interface{}) (v interface{}) {
 	return
 }
 
+// Find returns the case of count. It tries, in order, the case keyed by count
+// itself, the expression cases and the CLDR plural category (zero, one, two,
+// few, many or other) of count in the Locale.
 func (p *Plural) Find(count interface{}) (v interface{}, ok bool) {
+	operand, numeric := pluralOperand(count)
+
 	if p.Cases != nil {
 		if v, ok = p.Cases[count]; ok {
 			return
 		}
+		if i, isInt := operand.(int64); isInt && numeric {
+			if v, ok = p.Cases[int(i)]; ok {
+				return
+			}
+			if v, ok = p.Cases[strconv.FormatInt(i, 10)]; ok {
+				return
+			}
+		}
 	}
 	if p.ExpCases != nil {
 		var k PluralKeyCount
@@ -128,21 +151,101 @@ func (p *Plural) Find(count interface{}) (v interface{}, ok bool) {
 	}
 
 	if p.Cases != nil {
-		switch count {
-		case 1:
-			if v, ok = p.Cases["one"]; ok {
-				return
-			}
-		default:
-			if v, ok = p.Cases["other"]; ok {
+		if numeric {
+			if v, ok = p.Cases[string(p.Category(operand))]; ok {
 				return
 			}
 		}
+		if v, ok = p.Cases["other"]; ok {
+			return
+		}
 	}
 	v = nil
 	return
 }
 
+// Category returns the CLDR plural category of count in the Locale. Locales
+// without plural rules use the "one" category for 1 and "other" for the rest.
+func (p *Plural) Category(count interface{}) language.Plural {
+	if p.Ordinal {
+		return OrdinalCategory(p.Locale, count)
+	}
+	operand, numeric := pluralOperand(count)
+	if !numeric {
+		return language.Other
+	}
+	if spec := language.GetPluralSpec(p.Locale); spec != nil {
+		if category, err := spec.Plural(operand); err == nil {
+			return category
+		}
+		return language.Other
+	}
+	switch o := operand.(type) {
+	case int64:
+		if o == 1 || o == -1 {
+			return language.One
+		}
+	}
+	return language.Other
+}
+
+// pluralOperand converts count to a value accepted by the CLDR plural rules:
+// an int64 for integers and a decimal string for the others.
+func pluralOperand(count interface{}) (interface{}, bool) {
+	switch c := count.(type) {
+	case int:
+		return int64(c), true
+	case int8:
+		return int64(c), true
+	case int16:
+		return int64(c), true
+	case int32:
+		return int64(c), true
+	case int64:
+		return c, true
+	case uint:
+		return uintOperand(uint64(c))
+	case uint8:
+		return int64(c), true
+	case uint16:
+		return int64(c), true
+	case uint32:
+		return int64(c), true
+	case uint64:
+		return uintOperand(c)
+	case float32:
+		return floatOperand(float64(c), 32)
+	case float64:
+		return floatOperand(c, 64)
+	case string:
+		if _, err := strconv.ParseFloat(c, 64); err != nil || c == "" {
+			return count, false
+		}
+		if i, err := strconv.ParseInt(c, 10, 64); err == nil {
+			return i, true
+		}
+		return c, true
+	}
+	return count, false
+}
+
+func uintOperand(c uint64) (interface{}, bool) {
+	if c > math.MaxInt64 {
+		return strconv.FormatUint(c, 10), true
+	}
+	return int64(c), true
+}
+
+func floatOperand(c float64, bitSize int) (interface{}, bool) {
+	if math.IsNaN(c) || math.IsInf(c, 0) {
+		return c, false
+	}
+	if c == math.Trunc(c) && math.Abs(c) < math.MaxInt64 {
+		return int64(c), true
+	}
+	return strconv.FormatFloat(c, 'f', -1, bitSize), true
+}
+
 func ParsePlural(data interface{}) *Plural {
 	p := &Plural{}
 	switch d := data.(type) {
@@ -0,0 +1,75 @@
+package i18nmod_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestPluralFind(t *testing.T) {
+	cases := map[string]map[interface{}]string{
+		"ru": {1: "one", 21: "one", 2: "few", int64(3): "few", 5: "many", 11: "many", "1.5": "other", 0: "zero-exact"},
+		"pl": {uint(1): "one", 22: "few", 25: "many", 1.5: "other"},
+		"en": {int64(1): "one", 1.0: "one", "1": "one", 2: "other", float32(2.5): "other"},
+		"_":  {int8(1): "one", 7: "other"},
+	}
+
+	for locale, counts := range cases {
+		p := &i18nmod.Plural{Locale: locale}
+		for _, key := range []string{"one", "few", "many", "other"} {
+			p.AddCase(key, key)
+		}
+		p.AddCase(0, "zero-exact")
+
+		for count, expected := range counts {
+			if v, ok := p.Find(count); !ok || v != expected {
+				t.Errorf("%s: %T(%v): expected %q, got %v", locale, count, count, expected, v)
+			}
+		}
+	}
+}
+
+func TestPluralizeError(t *testing.T) {
+	group, source := "messages", "messages/en.yaml"
+	tn := &i18nmod.Translation{Group: &group, Source: &source, Key: "files", Plural: &i18nmod.Plural{Locale: "en", Cases: map[interface{}]interface{}{
+		"one":   i18nmod.NewLazyTemplate("{{count}} file"),
+		"other": i18nmod.NewLazyTemplate("{{count files"),
+	}}, Ordinal: &i18nmod.Plural{Locale: "en", Ordinal: true, Cases: map[interface{}]interface{}{
+		"other": i18nmod.NewLazyTemplate("{{index .Names 5}}"),
+	}}}
+
+	if s, err := tn.Pluralize(1, nil); err != nil || s != "1 file" {
+		t.Errorf("expected %q, got %q, %v", "1 file", s, err)
+	}
+	for name, f := range map[string]func(count interface{}, data interface{}) (string, error){
+		"plural":  tn.Pluralize,
+		"ordinal": tn.Ordinalize,
+	} {
+		s, err := f(2, map[string][]string{"Names": {"a"}})
+		terr, ok := err.(*i18nmod.TranslationError)
+		if !ok {
+			t.Errorf("%s: expected *TranslationError, got %T %v", name, err, err)
+			continue
+		}
+		if s != "" || terr.Group != group || terr.Key != "files" || terr.Locale != "en" || terr.Source != source {
+			t.Errorf("%s: invalid error %q: %v", name, s, terr)
+		}
+	}
+
+	// the missing cases and the nested cases are errors, the scalars are
+	// formatted
+	tn = &i18nmod.Translation{Group: &group, Key: "files", Plural: &i18nmod.Plural{Locale: "en", Cases: map[interface{}]interface{}{
+		"one": 1,
+		2:     &i18nmod.Select{},
+	}}}
+	if s, err := tn.Pluralize(1, nil); err != nil || s != "1" {
+		t.Errorf("expected %q, got %q, %v", "1", s, err)
+	}
+	for _, count := range []int{2, 3} {
+		if s, err := tn.Pluralize(count, nil); err == nil {
+			t.Errorf("%d: expected error, got %q", count, s)
+		} else if _, ok := err.(*i18nmod.TranslationError); !ok {
+			t.Errorf("%d: expected *TranslationError, got %T %v", count, err, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package i18nmod
+
+import (
+	"fmt"
+)
+
+// Select is a translation that varies by a choice, like the grammatical gender.
+// Its cases are strings, templates or nested Plural and Select values.
+type Select struct {
+	Cases map[string]interface{}
+}
+
+func (s *Select) AddCase(key string, value interface{}) {
+	if s.Cases == nil {
+		s.Cases = map[string]interface{}{}
+	}
+	s.Cases[key] = value
+}
+
+// Find returns the case of choice, or the "other" case.
+func (s *Select) Find(choice interface{}) (v interface{}, ok bool) {
+	if choice != nil {
+		if v, ok = s.Cases[fmt.Sprint(choice)]; ok {
+			return
+		}
+	}
+	v, ok = s.Cases["other"]
+	return
+}
+
+// resolveCase follows the nested Select and Plural cases of value using the
+// select and count values of tl.
+func resolveCase(value interface{}, tl *T) (interface{}, error) {
+	selects := tl.SelectValues
+	for {
+		var ok bool
+		switch v := value.(type) {
+		case *Select:
+			var choice interface{}
+			if len(selects) > 0 {
+				choice, selects = selects[0], selects[1:]
+			}
+			if value, ok = v.Find(choice); !ok {
+				return nil, fmt.Errorf("no select case for %v", choice)
+			}
+		case *Plural:
+			if tl.CountValue == nil {
+				return nil, fmt.Errorf("count value is required")
+			}
+			if value, ok = v.Find(tl.CountValue); !ok {
+				return nil, fmt.Errorf("no plural case for %v", tl.CountValue)
+			}
+		default:
+			return value, nil
+		}
+	}
+}
+
+// translateCases sets on r the case of value selected by tl, executing it if it
+// is a template.
+func (t *Translation) translateCases(value interface{}, tl *T, r *Result) {
+	value, err := resolveCase(value, tl)
+	if err != nil {
+		r.Error = err
+		return
+	}
+
+	vt, err := caseExecutor(value)
+	if err != nil {
+		r.Error = fmt.Errorf("Parse template failed: %v", err)
+		return
+	}
+	if vt != nil {
+		var data interface{}
+		if tfd, ok := tl.DataValue.(TemplateFuncsData); ok {
+			data = tfd.Data()
+			vt = vt.Funcs(tl.funcMaps...).Funcs(tfd.Funcs()).FuncsValues(tfd.FuncValues())
+		} else {
+			data = tl.DataValue
+			vt = vt.Funcs(tl.funcMaps...).FuncsValues(tl.funcValues...)
+		}
+
+		if r.value, err = vt.ExecuteString(data, map[string]interface{}{
+			"count": func() interface{} {
+				return tl.CountValue
+			},
+		}); err != nil {
+			r.Error = fmt.Errorf("Execute template failed: %v", err)
+		}
+	} else {
+		r.value = fmt.Sprint(value)
+	}
+}
+
+// setCasesLocale sets the locale of the plurals in value and in its cases.
+func setCasesLocale(value interface{}, lang string) {
+	switch v := value.(type) {
+	case *Plural:
+		if v == nil {
+			return
+		}
+		if v.Locale == "" {
+			v.Locale = lang
+		}
+		for _, c := range v.Cases {
+			setCasesLocale(c, lang)
+		}
+		for _, c := range v.ExpCases {
+			setCasesLocale(c, lang)
+		}
+	case *Select:
+		if v == nil {
+			return
+		}
+		for _, c := range v.Cases {
+			setCasesLocale(c, lang)
+		}
+	}
+}
@@ -14,6 +14,7 @@ type Key struct {
 	Prev       *Key
 	IsSingular bool
 	IsPlural   bool
+	IsOrdinal  bool
 	Cached     bool
 }
 
@@ -38,6 +39,11 @@ func (k *Key) Singular() *Key {
 	return k
 }
 
+func (k *Key) Ordinal() *Key {
+	k.IsOrdinal = true
+	return k
+}
+
 func NewKey(key string, prev *Key) *Key {
 	var cached bool
 	if key[0] == '^' {
@@ -85,6 +91,7 @@ type T struct {
 	DefaultValue     interface{}
 	DataValue        interface{}
 	CountValue       interface{}
+	SelectValues     []interface{}
 	AsTemplateResult bool
 	funcMaps         []funcs.FuncMap
 	funcValues       []funcs.FuncValues
@@ -119,6 +126,20 @@ func (t *T) Plural(value interface{}) *T {
 	return t
 }
 
+// Select sets the choices of the select translations, like the gender. Each
+// nested select level uses the next value.
+func (t *T) Select(values ...interface{}) *T {
+	t.SelectValues = append(t.SelectValues, values...)
+	return t
+}
+
+// Ordinal uses the ordinal form of the translation (1st, 2nd, 3rd) for value.
+func (t *T) Ordinal(value interface{}) *T {
+	t.CountValue = value
+	t.Key.Ordinal()
+	return t
+}
+
 func (t *T) Singular(value int) *T {
 	t.Key.Singular()
 	return t
@@ -174,8 +195,8 @@ func (t *T) AsTemplate() *T {
 
 var FOLLOW = 5
 
-func (t *T) Get() string {
-	var r *Result
+// result returns the handler result of t, following the aliases.
+func (t *T) result() (r *Result) {
 	for i := 0; i < FOLLOW; i++ {
 		r = t.Handler.Handle(t)
 		if r.Error != nil || r.Alias == "" {
@@ -183,31 +204,30 @@ func (t *T) Get() string {
 		}
 		t.With(r.Alias)
 	}
+	return
+}
 
+// GetE returns the translated text, or the error of the translation, that is
+// a *TranslationError for the errors of the Translator.
+func (t *T) GetE() (string, error) {
+	r := t.result()
 	if r.Error != nil {
-		return fmt.Sprint("ERROR: ", r.Error)
-	}
-
-	if r.value == nil {
-		if r.defaultValue != nil {
-			switch dvt := r.defaultValue.(type) {
-			case func() string:
-				r.value = dvt()
-			default:
-				r.value = dvt
-			}
-		}
+		return "", r.Error
 	}
+	return r.text(), nil
+}
 
-	if r.value == nil {
-		return ""
-	}
+func (t *T) Get() string {
+	r := t.result()
 
-	if s, ok := r.value.(string); ok {
-		return s
+	if r.Error != nil {
+		if r.translator != nil {
+			return r.translator.handleError(r)
+		}
+		return fmt.Sprint("ERROR: ", r.Error)
 	}
 
-	return fmt.Sprint(r.value)
+	return r.text()
 }
 
 func (t *T) GetText() string {
@@ -225,9 +245,36 @@ func (t *T) String() string {
 type Result struct {
 	defaultValue interface{}
 	value        interface{}
+	translator   *Translator
 	Alias        string
 	Error        error
 	Translation  *Translation
+	// Locale is the locale of the Translation
+	Locale string
+}
+
+// text returns the value, or the default value if it is nil.
+func (r *Result) text() string {
+	if r.value == nil {
+		if r.defaultValue != nil {
+			switch dvt := r.defaultValue.(type) {
+			case func() string:
+				r.value = dvt()
+			default:
+				r.value = dvt
+			}
+		}
+	}
+
+	if r.value == nil {
+		return ""
+	}
+
+	if s, ok := r.value.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(r.value)
 }
 
 func Cached(key string) string {
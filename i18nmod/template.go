@@ -0,0 +1,55 @@
+package i18nmod
+
+import (
+	"sync"
+
+	"github.com/moisespsena/template/text/template"
+)
+
+// LazyTemplate is a template text parsed on its first use. It is the template
+// value of the plural and select cases, and of the compiled translations,
+// that keep the text to skip the parse at startup.
+type LazyTemplate struct {
+	Text string
+	once sync.Once
+	exec *template.Executor
+	err  error
+}
+
+// NewLazyTemplate returns the template of text, parsed by the first Executor
+// call.
+func NewLazyTemplate(text string) *LazyTemplate {
+	return &LazyTemplate{Text: text}
+}
+
+// ParseLazyTemplate returns the template of text parsed now.
+func ParseLazyTemplate(text string) (*LazyTemplate, error) {
+	t := &LazyTemplate{Text: text}
+	if _, err := t.Executor(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Executor returns the parsed template.
+func (t *LazyTemplate) Executor() (*template.Executor, error) {
+	t.once.Do(func() {
+		var tpl *template.Template
+		if tpl, t.err = template.New("").Parse(t.Text); t.err == nil {
+			t.exec = tpl.CreateExecutor()
+		}
+	})
+	return t.exec, t.err
+}
+
+// caseExecutor returns the executor of the template case value, or nil if it
+// is not a template.
+func caseExecutor(value interface{}) (*template.Executor, error) {
+	switch v := value.(type) {
+	case *template.Executor:
+		return v, nil
+	case *LazyTemplate:
+		return v.Executor()
+	}
+	return nil, nil
+}
@@ -0,0 +1,129 @@
+package i18nmod
+
+import (
+	"context"
+	"sync"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx with the tenant id. The contexts of the
+// tenant are created by Context.WithContext:
+//
+//	c = c.WithContext(i18nmod.WithTenant(r.Context(), "acme"))
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFromContext returns the tenant id of ctx.
+func TenantFromContext(ctx context.Context) (id string, ok bool) {
+	if ctx == nil {
+		return
+	}
+	id, ok = ctx.Value(tenantKey{}).(string)
+	return
+}
+
+// Overlay is a sparse set of translations of a tenant, by group and locale,
+// translated before the Translator ones. It is safe for concurrent use.
+type Overlay struct {
+	mu     sync.RWMutex
+	groups map[string]map[string]DB
+}
+
+// Set adds or replaces the translations of group and lang.
+func (o *Overlay) Set(lang string, group string, t ...*Translation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.groups == nil {
+		o.groups = map[string]map[string]DB{}
+	}
+	if o.groups[group] == nil {
+		o.groups[group] = map[string]DB{}
+	}
+	db := o.groups[group][lang]
+	if db == nil {
+		db = DB{}
+		o.groups[group][lang] = db
+	}
+	for _, t := range t {
+		t := *t
+		t.Group = &group
+		setCasesLocale(t.Plural, lang)
+		setCasesLocale(t.Ordinal, lang)
+		setCasesLocale(t.Select, lang)
+		db[t.Key] = &t
+	}
+}
+
+// SetGroup adds or replaces the translations of tree, like the ones loaded by
+// a Backend.
+func (o *Overlay) SetGroup(lang string, group string, tree *Tree) {
+	var items []*Translation
+	for _, t := range treeDB(lang, group, tree) {
+		items = append(items, t)
+	}
+	o.Set(lang, group, items...)
+}
+
+// Delete removes the translations of keys, so the Translator ones are used
+// again.
+func (o *Overlay) Delete(lang string, group string, keys ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if db := o.groups[group][lang]; db != nil {
+		for _, key := range keys {
+			delete(db, key)
+		}
+	}
+}
+
+// Get returns the translation of key in the first of locales that has it.
+func (o *Overlay) Get(group, key string, locales ...string) (tn *Translation, lang string) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if data, ok := o.groups[group]; ok {
+		for _, lang = range locales {
+			if tn, ok = data[lang][key]; ok {
+				return
+			}
+		}
+	}
+	return nil, ""
+}
+
+// Tenant returns the overlay of the tenant id, creating it if not exists.
+func (tr *Translator) Tenant(id string) *Overlay {
+	tr.Lock()
+	defer tr.Unlock()
+	if tr.tenants == nil {
+		tr.tenants = map[string]*Overlay{}
+	}
+	o := tr.tenants[id]
+	if o == nil {
+		o = &Overlay{}
+		tr.tenants[id] = o
+	}
+	return o
+}
+
+// RemoveTenant removes the overlay of the tenant id.
+func (tr *Translator) RemoveTenant(id string) {
+	tr.Lock()
+	defer tr.Unlock()
+	delete(tr.tenants, id)
+}
+
+// overlay returns the overlay of the tenant of ctx, or nil.
+func (tr *Translator) overlay(ctx Context) *Overlay {
+	if ctx == nil {
+		return nil
+	}
+	id, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tr.RLock()
+	defer tr.RUnlock()
+	return tr.tenants[id]
+}
@@ -0,0 +1,63 @@
+package i18nmod_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+func TestTenantOverlay(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.DefaultLocale = "en"
+	tr.AddBackend(memBackend{"messages": {
+		"en": {"hello": "Hello", "bye": "Bye"},
+		"pt": {"hello": "Olá"},
+	}})
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	acme := tr.Tenant("acme")
+	acme.Set("en", "messages", &i18nmod.Translation{Key: "hello", Value: "Welcome to ACME"})
+	tree := &i18nmod.Tree{}
+	tree.Add(&i18nmod.Translation{Key: "title", Value: "ACME"})
+	acme.SetGroup("pt", "messages", tree)
+
+	base := tr.NewContext("pt")
+	tenant := base.WithContext(i18nmod.WithTenant(context.Background(), "acme"))
+	other := base.WithContext(i18nmod.WithTenant(context.Background(), "other"))
+	for _, c := range []struct {
+		ctx      i18nmod.Context
+		key      string
+		expected string
+	}{
+		{base, "^messages.bye", "Bye"},
+		{tenant, "^messages.bye", "Bye"},
+		{base, "messages.title", "messages.title"},
+		{tenant, "messages.title", "ACME"},
+		// the tenant overrides only its locale
+		{tenant, "^messages.hello", "Olá"},
+		{tr.NewContext("en").WithContext(i18nmod.WithTenant(context.Background(), "acme")), "messages.hello", "Welcome to ACME"},
+		{tr.NewContext("en"), "messages.hello", "Hello"},
+		{other, "messages.title", "messages.title"},
+	} {
+		if got := c.ctx.T(c.key).Get(); got != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.key, c.expected, got)
+		}
+	}
+
+	// the overlay is updated at runtime
+	acme.Delete("pt", "messages", "title")
+	if got := tenant.T("messages.title").Get(); got != "messages.title" {
+		t.Errorf("expected %q, got %q", "messages.title", got)
+	}
+	tr.RemoveTenant("acme")
+	en := tr.NewContext("en").WithContext(i18nmod.WithTenant(context.Background(), "acme"))
+	if got := en.T("messages.hello").Get(); got != "Hello" {
+		t.Errorf("expected %q, got %q", "Hello", got)
+	}
+	if id, ok := i18nmod.TenantFromContext(en); !ok || id != "acme" {
+		t.Errorf("invalid tenant %q", id)
+	}
+}
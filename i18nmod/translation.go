@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/moisespsena/template/text/template"
 )
@@ -13,30 +14,71 @@ type Translation struct {
 	Key           string
 	Value         string
 	ValueTemplate *template.Executor
+	// Template is the value template parsed on first use, if ValueTemplate is
+	// nil.
+	Template      *LazyTemplate
 	Plural        *Plural
+	Ordinal       *Plural
+	Select        *Select
+	Message       *Message
 	Source        *string
 	Alias         string
 	TemplateCache *template.Executor
 }
 
-func (t *Translation) Pluralize(count interface{}, data interface{}) string {
-	v, ok := t.Plural.Find(count)
+// Pluralize returns the Plural case of count, executing it with the count
+// template function if it is a template. The errors are *TranslationError.
+func (t *Translation) Pluralize(count interface{}, data interface{}) (string, error) {
+	return t.pluralize(t.Plural, count, data)
+}
+
+// Ordinalize returns the Ordinal case of count, executing it with the count
+// template function if it is a template. The errors are *TranslationError.
+func (t *Translation) Ordinalize(count interface{}, data interface{}) (string, error) {
+	return t.pluralize(t.Ordinal, count, data)
+}
+
+func (t *Translation) pluralize(p *Plural, count interface{}, data interface{}) (string, error) {
+	v, ok := p.Find(count)
 	if !ok {
-		return ""
+		return "", t.error(p, fmt.Errorf("no plural case for %v", count))
 	}
 
-	if tpl, ok := v.(*template.Executor); ok {
+	tpl, err := caseExecutor(v)
+	if err != nil {
+		return "", t.error(p, fmt.Errorf("Parse template failed: %v", err))
+	}
+	if tpl != nil {
 		s, err := tpl.ExecuteString(data, map[string]interface{}{
 			"count": func() interface{} {
 				return count
 			},
 		})
 		if err != nil {
-			return fmt.Sprint("Translation template error [%v]: %v", t.Key, err)
+			return "", t.error(p, fmt.Errorf("Execute template failed: %v", err))
 		}
-		return s
+		return s, nil
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case *Plural, *Select, nil:
+		return "", t.error(p, fmt.Errorf("unsupported case value %T", v))
+	default:
+		return fmt.Sprint(v), nil
 	}
-	return v.(string)
+}
+
+// error returns the *TranslationError of t with err.
+func (t *Translation) error(p *Plural, err error) *TranslationError {
+	e := &TranslationError{Key: t.Key, Locale: p.Locale, Err: err}
+	if t.Group != nil {
+		e.Group = *t.Group
+	}
+	if t.Source != nil {
+		e.Source = *t.Source
+	}
+	return e
 }
 
 func (t *Translation) PluralValue() interface{} {
@@ -47,6 +89,24 @@ func (t *Translation) SingularValue() interface{} {
 	return t.Plural.MustFind("s")
 }
 
+var templateCacheMu sync.Mutex
+
+// valueTemplate returns Value parsed as template. It is parsed once and kept
+// in TemplateCache.
+func (t *Translation) valueTemplate() (*template.Executor, error) {
+	templateCacheMu.Lock()
+	defer templateCacheMu.Unlock()
+
+	if t.TemplateCache == nil {
+		tpl, err := template.New("").Parse(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		t.TemplateCache = tpl.CreateExecutor()
+	}
+	return t.TemplateCache, nil
+}
+
 func (t *Translation) Translate(context Context, lang string, tl *T, r *Result) {
 	r.Translation = t
 
@@ -59,11 +119,41 @@ func (t *Translation) Translate(context Context, lang string, tl *T, r *Result)
 		return
 	}
 
+	if t.Message != nil {
+		t.translateMessage(lang, tl, r)
+		return
+	}
+
+	if t.Select != nil {
+		t.translateCases(t.Select, tl, r)
+		return
+	}
+
+	if tl.CountValue != nil {
+		if tl.Key.IsOrdinal && t.Ordinal == nil {
+			r.Error = errors.New("no ordinal cases")
+			return
+		} else if t.Ordinal != nil && (tl.Key.IsOrdinal || t.Plural == nil) {
+			t.translateCases(t.Ordinal, tl, r)
+			return
+		} else if t.Plural != nil {
+			t.translateCases(t.Plural, tl, r)
+			return
+		}
+	}
+
+	valueTemplate := t.ValueTemplate
+	if valueTemplate == nil && t.Template != nil {
+		var err error
+		if valueTemplate, err = t.Template.Executor(); err != nil {
+			r.Error = fmt.Errorf("Parse template failed: %v", err)
+			return
+		}
+	}
+
 	if t.Plural != nil {
 		var value interface{}
-		if tl.CountValue != nil {
-			value = t.Pluralize(tl.CountValue, tl.DataValue)
-		} else if tl.Key.IsSingular {
+		if tl.Key.IsSingular {
 			value = t.SingularValue()
 		} else if tl.Key.IsPlural {
 			value = t.PluralValue()
@@ -71,8 +161,12 @@ func (t *Translation) Translate(context Context, lang string, tl *T, r *Result)
 			r.Error = errors.New("error: isn't singular or Plural or not have count value")
 			return
 		}
-		switch vt := value.(type) {
-		case *template.Executor:
+		vt, err := caseExecutor(value)
+		if err != nil {
+			r.Error = fmt.Errorf("Parse template failed: %v", err)
+			return
+		}
+		if vt != nil {
 			var data interface{}
 			if tfd, ok := tl.DataValue.(TemplateFuncsData); ok {
 				data = tfd.Data()
@@ -82,22 +176,21 @@ func (t *Translation) Translate(context Context, lang string, tl *T, r *Result)
 				vt = vt.Funcs(tl.funcMaps...).FuncsValues(tl.funcValues...)
 			}
 
-			var err error
 			if r.value, err = vt.ExecuteString(data); err != nil {
 				r.Error = fmt.Errorf("Execute template failed: %v", err)
 			}
-		default:
-			r.value = vt.(string)
+		} else {
+			r.value = value.(string)
 		}
 		return
-	} else if t.ValueTemplate != nil {
+	} else if valueTemplate != nil {
 		var buf bytes.Buffer
 		var err error
 
 		if tfd, ok := tl.DataValue.(TemplateFuncsData); ok {
-			err = t.ValueTemplate.Funcs(tfd.Funcs()).Execute(&buf, tfd.Data())
+			err = valueTemplate.Funcs(tfd.Funcs()).Execute(&buf, tfd.Data())
 		} else {
-			err = t.ValueTemplate.Funcs(tl.funcMaps...).Execute(&buf, tl.DataValue)
+			err = valueTemplate.Funcs(tl.funcMaps...).Execute(&buf, tl.DataValue)
 		}
 
 		if err != nil {
@@ -107,23 +200,18 @@ func (t *Translation) Translate(context Context, lang string, tl *T, r *Result)
 
 		r.value = buf.String()
 		return
-	} else if tl.AsTemplateResult || t.ValueTemplate != nil {
-		var tpl *template.Executor
-		if t.TemplateCache != nil {
-			tpl = t.TemplateCache
-		} else if t.ValueTemplate == nil {
-			tpl, err := template.New("").Parse(t.Value)
-			if err != nil {
-				r.Error = fmt.Errorf("Parse Value failed: %v", err)
-				return
-			}
-			t.TemplateCache = tpl.CreateExecutor()
-		} else {
-			tpl = t.ValueTemplate
-			t.TemplateCache = tpl
+	} else if tl.AsTemplateResult || valueTemplate != nil {
+		var (
+			tpl *template.Executor
+			err error
+		)
+		if valueTemplate != nil {
+			tpl = valueTemplate
+		} else if tpl, err = t.valueTemplate(); err != nil {
+			r.Error = fmt.Errorf("Parse Value failed: %v", err)
+			return
 		}
 		var buf bytes.Buffer
-		var err error
 
 		var executor *template.Executor
 		var data interface{}
@@ -47,13 +47,28 @@ type Translator struct {
 	Cache                    *Cache
 	Locales                  []string
 	DefaultLocale            string
+	// Fallbacks maps a locale to the locales tried after it. See FallbackChain.
+	Fallbacks map[string][]string
+	// ErrorMode sets how T.Get renders the translation errors. T.GetE always
+	// returns them.
+	ErrorMode ErrorMode
+	// ErrorHandler receives the errors of ErrorReport mode. If nil, they are
+	// logged.
+	ErrorHandler func(err error)
+	// Missing collects the keys not found by Translate, if set.
+	Missing *MissingCollector
+	// LazyLoad enables loading each group locale from the backends on its
+	// first lookup, instead of requiring Preload.
+	LazyLoad bool
 	sync.RWMutex
 	preloaded           map[string]bool
 	groupLoadedCallback map[string][]func(lang string, db *ChildDB)
+	loaders             map[string]*groupLoader
+	tenants             map[string]*Overlay
 }
 
 func NewTranslator() *Translator {
-	return &Translator{
+	t := &Translator{
 		Backends:                 []Backend{},
 		ContextFactory:           DefaultContextFactory,
 		Cache:                    NewCache(),
@@ -61,9 +76,15 @@ func NewTranslator() *Translator {
 		groupLoadedCallback:      make(map[string][]func(lang string, db *ChildDB)),
 		Groups:                   make(map[string]map[string]DB),
 	}
+	return t
 }
 
+// AfterGroupLoad registers cb to be called every time a locale of groupName is
+// loaded. Callbacks run with the translator locked for writing, so they may
+// change db but must not call other Translator methods.
 func (t *Translator) AfterGroupLoad(groupName string, cb func(lang string, db *ChildDB)) {
+	t.Lock()
+	defer t.Unlock()
 	t.groupLoadedCallback[groupName] = append(t.groupLoadedCallback[groupName], cb)
 	if data, ok := t.Groups[groupName]; ok {
 		for lang, db := range data {
@@ -73,18 +94,38 @@ func (t *Translator) AfterGroupLoad(groupName string, cb func(lang string, db *C
 }
 
 func (t *Translator) OnContextCreate(callbacks ...func(context Context)) *Translator {
+	t.Lock()
+	defer t.Unlock()
 	t.OnContextCreateCallbacks = append(t.OnContextCreateCallbacks, callbacks...)
 	return t
 }
 
 func (t *Translator) AddBackend(backends ...Backend) {
+	t.Lock()
+	defer t.Unlock()
 	t.Backends = append(t.Backends, backends...)
 }
 
+func (t *Translator) backends() []Backend {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Backends
+}
+
 func (tr *Translator) LoadGroupTranslations(locale string, group string) (items DB, err error) {
+	tree, err := tr.LoadGroupTree(locale, group)
+	if err != nil {
+		return nil, err
+	}
+	return treeDB(locale, group, tree), nil
+}
+
+// LoadGroupTree returns the group translations of locale merged from all
+// backends.
+func (tr *Translator) LoadGroupTree(locale string, group string) (*Tree, error) {
 	tree := &Tree{}
 
-	for _, bc := range tr.Backends {
+	for _, bc := range tr.backends() {
 		t, err := bc.LoadTranslations(locale, group)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to load group '%v' translations of '%v' locale: %v", group, locale, err)
@@ -92,37 +133,60 @@ func (tr *Translator) LoadGroupTranslations(locale string, group string) (items
 		tree.Merge(t)
 	}
 
-	items = make(DB)
+	return tree, nil
+}
+
+// treeDB returns the translations of tree indexed by key, setting their key,
+// group and plurals locale.
+func treeDB(lang string, group string, tree *Tree) DB {
+	items := make(DB)
 
 	_ = tree.WalkT(func(key string, t *Translation) error {
 		t.Key = key
 		t.Group = &group
+		setCasesLocale(t.Plural, lang)
+		setCasesLocale(t.Ordinal, lang)
+		setCasesLocale(t.Select, lang)
 		items[key] = t
 		return nil
 	})
 
-	return
+	return items
 }
 
 func (tr *Translator) SetGroup(lang string, group string, tree *Tree) {
-	items := make(DB)
+	tr.setGroupDB(lang, group, treeDB(lang, group, tree))
+}
 
-	_ = tree.WalkT(func(key string, t *Translation) error {
-		t.Key = key
-		t.Group = &group
-		items[key] = t
-		return nil
-	})
+// Reload loads the group translations of locale from the backends again and
+// replaces the current ones. The AfterGroupLoad callbacks are called again.
+// A failed lazy load of the group locale is cleared.
+func (tr *Translator) Reload(locale string, group string) error {
+	items, err := tr.LoadGroupTranslations(locale, group)
+	if err != nil {
+		return err
+	}
+	tr.setGroupDB(locale, group, items)
+	tr.setLoaded(locale, group)
+	return nil
+}
+
+func (tr *Translator) setGroupDB(lang string, group string, items DB) {
+	tr.Lock()
+	defer tr.Unlock()
 
 	if _, ok := tr.Groups[group]; !ok {
 		tr.Groups[group] = map[string]DB{}
 	}
 	tr.Groups[group][lang] = items
+	tr.groupLoaded(group, lang)
+}
 
-	if callbacks := tr.groupLoadedCallback[group]; callbacks != nil {
-		for _, cb := range callbacks {
-			cb(lang, &ChildDB{Group: group, DB: tr.Groups[group][lang]})
-		}
+// groupLoaded calls the AfterGroupLoad callbacks of group. The caller must hold
+// the write lock.
+func (tr *Translator) groupLoaded(group, lang string) {
+	for _, cb := range tr.groupLoadedCallback[group] {
+		cb(lang, &ChildDB{Group: group, DB: tr.Groups[group][lang]})
 	}
 }
 
@@ -137,9 +201,11 @@ func (t *Translator) PreloadAll() error {
 }
 
 func (t *Translator) Preload(locales []string, names ...string) error {
+	backends := t.backends()
+
 	if len(locales) == 0 {
 		mn := set.New(set.ThreadSafe)
-		for _, backend := range t.Backends {
+		for _, backend := range backends {
 			for _, lang := range backend.ListLanguages() {
 				mn.Add(lang)
 			}
@@ -155,13 +221,13 @@ func (t *Translator) Preload(locales []string, names ...string) error {
 
 	if len(names) == 0 {
 		mn := set.New(set.NonThreadSafe)
-		for _, backend := range t.Backends {
+		for _, backend := range backends {
 			for _, name := range backend.ListGroups() {
 				mn.Add(name)
 			}
 		}
-		names = make([]string, mn.Size())
-		i := 0
+		names = make([]string, 0, mn.Size())
+		t.Lock()
 		if t.preloaded == nil {
 			t.preloaded = map[string]bool{}
 		}
@@ -171,16 +237,13 @@ func (t *Translator) Preload(locales []string, names ...string) error {
 				return true
 			}
 			t.preloaded[name] = true
-			names[i] = name
-			i++
+			names = append(names, name)
 			return true
 		})
+		t.Unlock()
 	}
 
 	for _, name := range names {
-		if _, ok := t.Groups[name]; !ok {
-			t.Groups[name] = make(map[string]DB)
-		}
 		for _, lang := range locales {
 			items, err := t.LoadGroupTranslations(lang, name)
 
@@ -188,53 +251,120 @@ func (t *Translator) Preload(locales []string, names ...string) error {
 				return err
 			}
 
-			if _, ok := t.Groups[name][lang]; !ok {
-				t.Groups[name][lang] = items
-			} else {
-				for k, tr := range items {
-					if _, ok := t.Groups[name][lang][k]; !ok {
-						t.Groups[name][lang][k] = tr
-					}
-				}
-			}
+			t.mergeGroup(name, lang, items)
+			t.loader(lang, name).once.Do(func() {})
+		}
+	}
+	return nil
+}
 
-			if callbacks := t.groupLoadedCallback[name]; callbacks != nil {
-				for _, cb := range callbacks {
-					cb(lang, &ChildDB{Group: name, DB: t.Groups[name][lang]})
-				}
+// mergeGroup adds to the group's locale DB the items it does not have yet.
+func (t *Translator) mergeGroup(name, lang string, items DB) {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, ok := t.Groups[name]; !ok {
+		t.Groups[name] = make(map[string]DB)
+	}
+
+	if _, ok := t.Groups[name][lang]; !ok {
+		t.Groups[name][lang] = items
+	} else {
+		for k, tr := range items {
+			if _, ok := t.Groups[name][lang][k]; !ok {
+				t.Groups[name][lang][k] = tr
 			}
 		}
 	}
-	return nil
+
+	t.groupLoaded(name, lang)
 }
 
 func (t *Translator) NewContext(lang string, defaultLocale ...string) (c Context) {
 	c = t.ContextFactory(t, t.Translate, lang, append(defaultLocale, AnyLang)...)
-	for _, cb := range t.OnContextCreateCallbacks {
+	t.RLock()
+	callbacks := t.OnContextCreateCallbacks
+	t.RUnlock()
+	for _, cb := range callbacks {
 		cb(c)
 	}
 	return c
 }
 
+// addMissing records the miss of the key in the locales.
+func (t *Translator) addMissing(group, name string, tl *T, locales []string) {
+	if t.Missing == nil || len(locales) == 0 {
+		return
+	}
+	var defaultValue string
+	if s, ok := tl.DefaultValue.(string); ok && s != tl.Key.Key {
+		defaultValue = s
+	}
+	for _, lang := range locales {
+		t.Missing.Add(group, name, lang, defaultValue)
+	}
+}
+
 func (t *Translator) Translate(context Context, tl *T) (r *Result) {
-	r = &Result{}
+	r = &Result{translator: t}
 	if tl.DefaultValue != nil {
 		r.defaultValue = tl.DefaultValue
 	} else {
 		r.defaultValue = tl.Key.Key
 	}
 
-	name := tl.Key.Name()
+	group, name := tl.Key.GroupName, tl.Key.Name()
 
+	defer func() {
+		if r.Error != nil {
+			if _, ok := r.Error.(*TranslationError); !ok {
+				err := &TranslationError{Group: group, Key: name, Locale: r.Locale, Err: r.Error}
+				if r.Translation != nil && r.Translation.Source != nil {
+					err.Source = *r.Translation.Source
+				}
+				r.Error = err
+			}
+		}
+	}()
+
+	// the load error of a locale is returned if no other locale has the key
+	var (
+		loadErr *TranslationError
+		missing []string
+	)
+	defer func() {
+		t.addMissing(group, name, tl, missing)
+	}()
+	overlay := t.overlay(context)
 	for _, lang := range tl.Locales {
-		if group, ok := t.Groups[tl.Key.GroupName]; ok {
-			if data, ok := group[lang]; ok {
-				if tn, ok := data[name]; ok {
-					tn.Translate(context, lang, tl, r)
-					return
+		if t.LazyLoad && group != "" {
+			if err := t.lazyLoad(lang, group); err != nil {
+				if loadErr == nil {
+					loadErr = &TranslationError{Group: group, Key: name, Locale: lang, Err: err}
 				}
+				continue
+			}
+		}
+		if overlay != nil {
+			if tn, _ := overlay.Get(group, name, lang); tn != nil {
+				r.Locale = lang
+				tn.Translate(context, lang, tl, r)
+				return
 			}
 		}
+		if tn, _ := t.Get(group, name, lang); tn != nil {
+			r.Locale = lang
+			tn.Translate(context, lang, tl, r)
+			return
+		}
+		if lang != AnyLang {
+			missing = append(missing, lang)
+		}
+	}
+
+	if loadErr != nil {
+		r.Locale, r.Error = loadErr.Locale, loadErr
+		return
 	}
 
 	if tl.DefaultValue != nil {
@@ -314,6 +444,41 @@ func (t *Translator) Translate(context Context, tl *T) (r *Result) {
 	return
 }
 
+// Get returns the translation of key in the first of locales that has it.
+func (t *Translator) Get(group, key string, locales ...string) (tn *Translation, lang string) {
+	t.RLock()
+	defer t.RUnlock()
+
+	if data, ok := t.Groups[group]; ok {
+		for _, lang = range locales {
+			if db, ok := data[lang]; ok {
+				if tn, ok = db[key]; ok {
+					return
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// handleError returns the text of the failed result r according to the
+// ErrorMode.
+func (t *Translator) handleError(r *Result) string {
+	switch t.ErrorMode {
+	case ErrorPanic:
+		panic(r.Error)
+	case ErrorReport:
+		if t.ErrorHandler != nil {
+			t.ErrorHandler(r.Error)
+		} else {
+			log.Error(r.Error)
+		}
+		r.Error, r.value = nil, nil
+		return r.text()
+	}
+	return fmt.Sprint("ERROR: ", r.Error)
+}
+
 func (tr *Translator) ValidOrDefaultLocale(l string) string {
 	if l != "" {
 		for _, loc := range tr.Locales {
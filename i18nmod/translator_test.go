@@ -0,0 +1,268 @@
+package i18nmod_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+type memBackend map[string]map[string]map[string]string
+
+func (b memBackend) ListGroups() (groups []string) {
+	for group := range b {
+		groups = append(groups, group)
+	}
+	return
+}
+
+func (b memBackend) ListLanguages() (langs []string) {
+	seen := map[string]bool{}
+	for _, locales := range b {
+		for lang := range locales {
+			if !seen[lang] {
+				seen[lang] = true
+				langs = append(langs, lang)
+			}
+		}
+	}
+	return
+}
+
+func (b memBackend) LoadTranslations(lang string, group string) (*i18nmod.Tree, error) {
+	tree := &i18nmod.Tree{}
+	for key, value := range b[group][lang] {
+		tree.Add(&i18nmod.Translation{Key: key, Value: value})
+	}
+	return tree, nil
+}
+
+func (b memBackend) SaveTranslation(*i18nmod.Translation) error   { return nil }
+func (b memBackend) DeleteTranslation(*i18nmod.Translation) error { return nil }
+
+func TestTranslatorConcurrentPreloadAndTranslate(t *testing.T) {
+	backend := memBackend{}
+	for g := 0; g < 10; g++ {
+		backend[fmt.Sprintf("g%d", g)] = map[string]map[string]string{
+			"en": {"hello": "Hello", "user.name": "User Name"},
+			"pt": {"hello": "Olá"},
+		}
+	}
+
+	tr := i18nmod.NewTranslator()
+	tr.DefaultLocale = "en"
+	tr.AddBackend(backend)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			if err := tr.PreloadAll(); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			tr.NewGroup("en", fmt.Sprintf("late%d", i), func(tree *i18nmod.Tree) {
+				tree.Add(&i18nmod.Translation{Key: "key", Value: "Late"})
+			})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			tr.AfterGroupLoad(fmt.Sprintf("g%d", i), func(lang string, db *i18nmod.ChildDB) {
+				db.Set(&i18nmod.Translation{Key: "extra", Value: "Extra"})
+			})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			ctx := tr.NewContext("pt", "en")
+			for j := 0; j < 100; j++ {
+				ctx.T(fmt.Sprintf("g%d.hello", i)).Get()
+				ctx.T(fmt.Sprintf("g%d.user.name", i)).Get()
+				ctx.TT(fmt.Sprintf("late%d.key", i)).Get()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	ctx := tr.NewContext("pt", "en")
+	for key, expected := range map[string]string{
+		"g3.hello":     "Olá",
+		"g3.user.name": "User Name",
+		"g3.extra":     "Extra",
+		"late3.key":    "Late",
+	} {
+		if got := ctx.T(key).Get(); got != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, got)
+		}
+	}
+}
+
+type countingBackend struct {
+	memBackend
+	mu    sync.Mutex
+	loads map[string]int
+}
+
+func (b *countingBackend) LoadTranslations(lang string, group string) (*i18nmod.Tree, error) {
+	b.mu.Lock()
+	b.loads[group+"["+lang+"]"]++
+	b.mu.Unlock()
+	return b.memBackend.LoadTranslations(lang, group)
+}
+
+func TestTranslatorLazyLoad(t *testing.T) {
+	backend := &countingBackend{
+		memBackend: memBackend{
+			"g1": {"en": {"hello": "Hello"}, "pt": {"hello": "Olá"}},
+			"g2": {"en": {"hello": "Hi"}},
+		},
+		loads: map[string]int{},
+	}
+
+	tr := i18nmod.NewTranslator()
+	tr.LazyLoad = true
+	tr.AddBackend(backend)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := tr.NewContext("pt").T("g1.hello").Get(); got != "Olá" {
+				t.Errorf("expected %q, got %q", "Olá", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := tr.NewContext("en").T("g1.hello").Get(); got != "Hello" {
+		t.Errorf("expected %q, got %q", "Hello", got)
+	}
+
+	expected := map[string]int{"g1[pt]": 1, "g1[en]": 1}
+	if len(backend.loads) != len(expected) {
+		t.Errorf("expected loads %v, got %v", expected, backend.loads)
+	}
+	for key, count := range expected {
+		if backend.loads[key] != count {
+			t.Errorf("%s: expected %d loads, got %d", key, count, backend.loads[key])
+		}
+	}
+}
+
+type failingBackend struct {
+	memBackend
+	failures int
+}
+
+func (b *failingBackend) LoadTranslations(lang string, group string) (*i18nmod.Tree, error) {
+	if b.failures > 0 {
+		b.failures--
+		return nil, errors.New("connection refused")
+	}
+	return b.memBackend.LoadTranslations(lang, group)
+}
+
+func TestTranslatorLazyLoadRetry(t *testing.T) {
+	backend := &failingBackend{memBackend: memBackend{"g": {"en": {"hello": "Hello"}}}, failures: 1}
+
+	tr := i18nmod.NewTranslator()
+	tr.LazyLoad = true
+	tr.AddBackend(backend)
+
+	// the failed load is tried again by the next lookup
+	ctx := tr.NewContext("en")
+	if _, err := ctx.T("g.hello").GetE(); err == nil {
+		t.Error("expected load error")
+	}
+	if got, err := ctx.T("g.hello").GetE(); err != nil || got != "Hello" {
+		t.Errorf("expected %q, got %q, %v", "Hello", got, err)
+	}
+
+	// the key of the next locale is used if a locale fails to load
+	backend.failures = 1
+	if got, err := tr.NewContext("pt", "en").T("g.hello").GetE(); err != nil || got != "Hello" {
+		t.Errorf("expected %q, got %q, %v", "Hello", got, err)
+	}
+
+	// Reload clears the failed load
+	backend.failures = 1
+	if _, err := tr.NewContext("pt").T("g.hello").GetE(); err == nil {
+		t.Error("expected load error")
+	}
+	backend.memBackend["g"]["pt"] = map[string]string{"hello": "Olá"}
+	if err := tr.Reload("pt", "g"); err != nil {
+		t.Fatal(err)
+	}
+	backend.failures = 1
+	if got, err := tr.NewContext("pt").T("g.hello").GetE(); err != nil || got != "Olá" {
+		t.Errorf("expected %q, got %q, %v", "Olá", got, err)
+	}
+}
+
+func TestTranslatorErrors(t *testing.T) {
+	tr := i18nmod.NewTranslator()
+	tr.NewGroup("en", "g", func(tree *i18nmod.Tree) {
+		msg, err := i18nmod.ParseMessage("Hello {name}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		source := "g/en.yaml"
+		tree.Add(&i18nmod.Translation{Key: "hello", Message: msg, Source: &source})
+	})
+	ctx := tr.NewContext("en")
+
+	_, err := ctx.T("g.hello").GetE()
+	terr, ok := err.(*i18nmod.TranslationError)
+	if !ok {
+		t.Fatalf("expected *TranslationError, got %#v", err)
+	}
+	if terr.Group != "g" || terr.Key != "hello" || terr.Locale != "en" || terr.Source != "g/en.yaml" {
+		t.Errorf("unexpected error fields: %#v", terr)
+	}
+	if s, err := ctx.T("g.hello").Data(map[string]string{"name": "Ana"}).GetE(); err != nil || s != "Hello Ana" {
+		t.Errorf("expected %q, got %q, %v", "Hello Ana", s, err)
+	}
+
+	var reported []error
+	tr.ErrorMode = i18nmod.ErrorReport
+	tr.ErrorHandler = func(err error) {
+		reported = append(reported, err)
+	}
+	if s := ctx.T("g.hello").Default("Hello!").Get(); s != "Hello!" {
+		t.Errorf("expected default value, got %q", s)
+	}
+	if len(reported) != 1 {
+		t.Errorf("expected 1 reported error, got %d", len(reported))
+	}
+
+	tr.ErrorMode = i18nmod.ErrorPanic
+	defer func() {
+		if _, ok := recover().(*i18nmod.TranslationError); !ok {
+			t.Error("expected panic with *TranslationError")
+		}
+	}()
+	ctx.T("g.hello").Get()
+}
+
+func TestLoadCompiled(t *testing.T) {
+	i18nmod.RegisterCompiled(func(tr *i18nmod.Translator) {
+		tr.NewGroup("en", "compiled", func(tree *i18nmod.Tree) {
+			tree.Add(&i18nmod.Translation{Key: "hello", Value: "Hello"})
+		})
+	})
+
+	// the compiled translations are set only by LoadCompiled
+	tr := i18nmod.NewTranslator()
+	if tn, _ := tr.Get("compiled", "hello", "en"); tn != nil {
+		t.Error("unexpected compiled translation of NewTranslator")
+	}
+	tr.LoadCompiled()
+	if got := tr.NewContext("en").T("compiled.hello").Get(); got != "Hello" {
+		t.Errorf("expected %q, got %q", "Hello", got)
+	}
+}
@@ -9,6 +9,14 @@ type Tree struct {
 	Parent   *Tree
 	Name     string
 	Children map[string]*Tree
+	// Links are the links of the children, by name.
+	Links map[string]*TreeLink
+}
+
+// TreeLink is a child linked to other tree node by Tree.Link.
+type TreeLink struct {
+	To     string
+	Source *string
 }
 
 func (t *Tree) Merge(tree *Tree) {
@@ -24,6 +32,13 @@ func (t *Tree) Merge(tree *Tree) {
 		t.Children = map[string]*Tree{}
 	}
 
+	for name, link := range tree.Links {
+		if t.Links == nil {
+			t.Links = map[string]*TreeLink{}
+		}
+		t.Links[name] = link
+	}
+
 	for name, child := range tree.Children {
 		cur, ok := t.Children[name]
 		if ok {
@@ -46,7 +61,7 @@ func (t *Tree) Tree(key string) *Tree {
 
 	for key[0] == '.' {
 		t = t.Parent
-		key = key[0:]
+		key = key[1:]
 	}
 
 	for _, name := range strings.Split(key, ".") {
@@ -73,13 +88,22 @@ func (t *Tree) Add(T *Translation) *Tree {
 func (t *Tree) Link(to string) *Tree {
 	tot := t.Parent.Tree(to)
 	t.Parent.Children[t.Name] = tot
+	if t.Parent.Links == nil {
+		t.Parent.Links = map[string]*TreeLink{}
+	}
+	t.Parent.Links[t.Name] = &TreeLink{To: to}
 	return tot
 }
 
-func (t *Tree) walkT(prefix string, f func(key string, t *Translation) error) (err error) {
-	if t.Children == nil {
+// walkT walks the children translations. The links to parent nodes are not
+// followed again.
+func (t *Tree) walkT(prefix string, path map[*Tree]bool, f func(key string, t *Translation) error) (err error) {
+	if t.Children == nil || path[t] {
 		return
 	}
+	path[t] = true
+	defer delete(path, t)
+
 	for name, child := range t.Children {
 		if child.T != nil {
 			if err = f(prefix+name, child.T); err != nil {
@@ -87,7 +111,7 @@ func (t *Tree) walkT(prefix string, f func(key string, t *Translation) error) (e
 			}
 		}
 		if child.Children != nil {
-			if err = child.walkT(prefix+name+".", f); err != nil {
+			if err = child.walkT(prefix+name+".", path, f); err != nil {
 				return
 			}
 		}
@@ -96,5 +120,5 @@ func (t *Tree) walkT(prefix string, f func(key string, t *Translation) error) (e
 }
 
 func (t *Tree) WalkT(f func(key string, t *Translation) error) (err error) {
-	return t.walkT("", f)
+	return t.walkT("", map[*Tree]bool{}, f)
 }
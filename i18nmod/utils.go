@@ -2,13 +2,16 @@ package i18nmod
 
 import (
 	"fmt"
+	"io/fs"
 	"io/ioutil"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
 
 	"github.com/moisespsena-go/path-helpers"
 	"github.com/moisespsena/template/text/template"
+	"github.com/nicksnyder/go-i18n/i18n/language"
 	"github.com/pkg/errors"
 )
 
@@ -23,9 +26,14 @@ func getExtension(fileName string) (extension string, err error) {
 }
 
 func WalkDir(name string, path string, cb func(key string, items []string) error) (err error) {
+	return WalkDirExt(name, path, cb, ".yaml")
+}
+
+// WalkDirExt is WalkDir of the files with one of the extensions exts.
+func WalkDirExt(name string, path string, cb func(key string, items []string) error, exts ...string) (err error) {
 	files, err := ioutil.ReadDir(path)
 	if err != nil {
-		return fmt.Errorf("i18nmod.WalkDir: Failed to scan '", path, "': ", err)
+		return fmt.Errorf("i18nmod.WalkDir: Failed to scan '%v': %v", path, err)
 	}
 
 	var items []string
@@ -36,9 +44,53 @@ func WalkDir(name string, path string, cb func(key string, items []string) error
 
 		if f.IsDir() {
 			if name == "" {
-				err = WalkDir(fname, p, cb)
+				err = WalkDirExt(fname, p, cb, exts...)
+			} else {
+				err = WalkDirExt(name+":"+fname, p, cb, exts...)
+			}
+			if err != nil {
+				return err
+			}
+		} else if !strings.HasPrefix(fname, ".") {
+			ext, err := getExtension(fname)
+			if err != nil {
+				return fmt.Errorf("i18nmod.DirIterate: Failed to scan '%v': %v", path, err)
+			} else {
+				for _, e := range exts {
+					if ext == e {
+						items = append(items, p)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if len(items) > 0 {
+		return cb(name, items)
+	}
+
+	return
+}
+
+// WalkFS is WalkDirExt of the dir of fsys. The item paths are the fsys paths.
+func WalkFS(fsys fs.FS, name string, dir string, cb func(key string, items []string) error, exts ...string) (err error) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18nmod.WalkFS: Failed to scan '%v': %v", dir, err)
+	}
+
+	var items []string
+
+	for _, f := range files {
+		fname := f.Name()
+		p := path.Join(dir, fname)
+
+		if f.IsDir() {
+			if name == "" {
+				err = WalkFS(fsys, fname, p, cb, exts...)
 			} else {
-				err = WalkDir(name+":"+fname, p, cb)
+				err = WalkFS(fsys, name+":"+fname, p, cb, exts...)
 			}
 			if err != nil {
 				return err
@@ -46,9 +98,13 @@ func WalkDir(name string, path string, cb func(key string, items []string) error
 		} else if !strings.HasPrefix(fname, ".") {
 			ext, err := getExtension(fname)
 			if err != nil {
-				return fmt.Errorf("i18nmod.DirIterate: Failed to scan '", path, "': ", err)
-			} else if ext == ".yaml" {
-				items = append(items, p)
+				return fmt.Errorf("i18nmod.WalkFS: Failed to scan '%v': %v", p, err)
+			}
+			for _, e := range exts {
+				if ext == e {
+					items = append(items, p)
+					break
+				}
 			}
 		}
 	}
@@ -64,6 +120,22 @@ func FormatGroupName(groupname string) string {
 	return strings.Replace(groupname, ".", "_", -1)
 }
 
+// FormatLang returns the language name of the inputs: "pt_br" gives "pt-BR".
+func FormatLang(lang string) (string, error) {
+	if lang == AnyLang {
+		return lang, nil
+	}
+	langs := language.Parse(lang)
+	if l := len(langs); l == 0 || l > 1 {
+		return "", fmt.Errorf("Invalid language name %q", lang)
+	}
+	lang = langs[0].Tag
+	if parts := strings.Split(lang, "-"); len(parts) > 1 {
+		lang = parts[0] + "-" + strings.ToUpper(parts[1])
+	}
+	return lang, nil
+}
+
 func PkgToGroup(pkgPath string, sub ...string) string {
 	p := []string{FormatGroupName(strings.Replace(strings.Replace(pkgPath, "\\", "/", -1),
 		"/", ":", -1))}
@@ -0,0 +1,287 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type reader struct {
+	dec *xml.Decoder
+	doc *Document
+	// data are the inline codes of the originalData of the unit
+	data map[string]string
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Read reads the XLIFF 1.2 or 2.0 document. The unknown elements are skipped.
+func Read(in io.Reader) (*Document, error) {
+	r := &reader{dec: xml.NewDecoder(in), doc: &Document{}}
+	for {
+		tok, err := r.dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("XLIFF root element not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if e, ok := tok.(xml.StartElement); ok {
+			if e.Name.Local != "xliff" {
+				return nil, fmt.Errorf("Invalid XLIFF root element %q", e.Name.Local)
+			}
+			switch r.doc.Version = attr(e, "version"); r.doc.Version {
+			case Version12:
+			case Version20:
+				r.doc.SourceLang, r.doc.TargetLang = attr(e, "srcLang"), attr(e, "trgLang")
+			default:
+				return nil, fmt.Errorf("Invalid XLIFF version %q", r.doc.Version)
+			}
+			if err = r.children(func(e xml.StartElement) error {
+				if e.Name.Local != "file" {
+					return r.dec.Skip()
+				}
+				return r.file(e)
+			}); err != nil {
+				return nil, err
+			}
+			return r.doc, nil
+		}
+	}
+}
+
+// children calls f with the child elements, until the end of the current one.
+func (r *reader) children(f func(e xml.StartElement) error) error {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err = f(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (r *reader) file(e xml.StartElement) error {
+	f := &File{}
+	if r.doc.Version == Version12 {
+		f.Group = attr(e, "original")
+		if r.doc.SourceLang == "" {
+			r.doc.SourceLang, r.doc.TargetLang = attr(e, "source-language"), attr(e, "target-language")
+		}
+	} else {
+		f.Group = attr(e, "id")
+	}
+	r.doc.Files = append(r.doc.Files, f)
+
+	var units func(e xml.StartElement) error
+	units = func(e xml.StartElement) error {
+		switch e.Name.Local {
+		case "body":
+			return r.children(units)
+		case "group":
+			if typ := attr(e, "restype") + attr(e, "type"); typ == "x-gettext-plurals" || typ == "x-i18nmod:plural" {
+				u, err := r.plural(e)
+				if err == nil {
+					f.Units = append(f.Units, u)
+				}
+				return err
+			}
+			return r.children(units)
+		case "trans-unit", "unit":
+			u := &Unit{Key: attr(e, "id")}
+			for kind, types := range kindTypes {
+				if typ := attr(e, "restype") + attr(e, "type"); typ == types[0] || typ == types[1] {
+					u.Kind = kind
+				}
+			}
+			if err := r.unit(u); err != nil {
+				return err
+			}
+			f.Units = append(f.Units, u)
+			return nil
+		}
+		return r.dec.Skip()
+	}
+	return r.children(units)
+}
+
+func (r *reader) plural(e xml.StartElement) (*Unit, error) {
+	u := &Unit{Key: attr(e, "id")}
+	err := r.children(func(e xml.StartElement) error {
+		switch e.Name.Local {
+		case "note", "notes":
+			return r.notes(e, u)
+		case "trans-unit", "unit":
+			id := attr(e, "id")
+			if !strings.HasPrefix(id, u.Key+"[") || !strings.HasSuffix(id, "]") {
+				return fmt.Errorf("Invalid plural unit id %q of %q", id, u.Key)
+			}
+			vu := &Unit{}
+			if err := r.unit(vu); err != nil {
+				return err
+			}
+			name := id[len(u.Key)+1 : len(id)-1]
+			if !validCase(strings.TrimPrefix(name, "ordinal:")) {
+				return fmt.Errorf("Invalid plural case %q of %q", name, u.Key)
+			}
+			u.Variants = append(u.Variants, &Variant{
+				Case:     strings.TrimPrefix(name, "ordinal:"),
+				Ordinal:  strings.HasPrefix(name, "ordinal:"),
+				Source:   vu.Source,
+				Target:   vu.Target,
+				State:    vu.State,
+				Template: vu.Template,
+			})
+			return nil
+		}
+		return r.dec.Skip()
+	})
+	return u, err
+}
+
+// validCase returns whether name is a plural case name, or an expression case
+// like "=0", ">%d10" or "<5" with its value.
+func validCase(name string) bool {
+	if name == "" {
+		return false
+	}
+	switch name[0] {
+	case '=', '>', '<':
+		v := name[1:]
+		if strings.HasPrefix(v, "%") {
+			if len(v) < 2 {
+				return false
+			}
+			v = v[2:]
+		}
+		return v != ""
+	}
+	return true
+}
+
+func (r *reader) notes(e xml.StartElement, u *Unit) error {
+	if e.Name.Local == "notes" {
+		return r.children(func(e xml.StartElement) error {
+			if e.Name.Local == "note" {
+				return r.notes(e, u)
+			}
+			return r.dec.Skip()
+		})
+	}
+	note, _, err := r.content()
+	if err == nil {
+		u.Notes = append(u.Notes, note)
+	}
+	return err
+}
+
+// unit reads the trans-unit (1.2) or unit (2.0) element.
+func (r *reader) unit(u *Unit) error {
+	r.data = map[string]string{}
+	var hasTarget bool
+	read := func(e xml.StartElement) (err error) {
+		var codes bool
+		switch e.Name.Local {
+		case "source":
+			u.Source, codes, err = r.content()
+		case "target":
+			hasTarget = true
+			u.Target, codes, err = r.content()
+			if state := attr(e, "state"); state != "" {
+				u.State = state20(state)
+			}
+		default:
+			return r.dec.Skip()
+		}
+		u.Template = u.Template || codes
+		return
+	}
+
+	err := r.children(func(e xml.StartElement) error {
+		switch e.Name.Local {
+		case "note", "notes":
+			return r.notes(e, u)
+		case "originalData":
+			return r.children(func(e xml.StartElement) error {
+				if e.Name.Local != "data" {
+					return r.dec.Skip()
+				}
+				code, _, err := r.content()
+				r.data[attr(e, "id")] = code
+				return err
+			})
+		case "segment":
+			u.State = attr(e, "state")
+			return r.children(read)
+		}
+		return read(e)
+	})
+	if u.State == "" {
+		u.State = StateInitial
+		if hasTarget && u.Target != "" {
+			u.State = StateTranslated
+		}
+	}
+	return err
+}
+
+// content returns the text of the current element, with the inline codes
+// replaced by their original data, and if it has codes. The paired codes
+// (g, pc, mrk) are replaced by their contents.
+func (r *reader) content() (text string, codes bool, err error) {
+	var b strings.Builder
+	for {
+		var tok xml.Token
+		if tok, err = r.dec.Token(); err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.EndElement:
+			return b.String(), codes, nil
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "ph", "bpt", "ept", "it":
+				codes = true
+				var code string
+				if code, _, err = r.content(); err != nil {
+					return
+				}
+				if ref := attr(t, "dataRef"); ref != "" {
+					code = r.data[ref]
+				}
+				b.WriteString(code)
+			case "g", "pc", "mrk", "sub":
+				var (
+					inner      string
+					innerCodes bool
+				)
+				if inner, innerCodes, err = r.content(); err != nil {
+					return
+				}
+				codes = codes || innerCodes
+				b.WriteString(inner)
+			default:
+				if err = r.dec.Skip(); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
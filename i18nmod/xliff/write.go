@@ -0,0 +1,257 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// part is a text or an inline code of a content.
+type part struct {
+	text string
+	code bool
+}
+
+// split splits the template text into the texts and the "{{...}}" codes.
+func split(text string, template bool) (parts []part) {
+	for template {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			break
+		}
+		end += start + 2
+		if start > 0 {
+			parts = append(parts, part{text: text[0:start]})
+		}
+		parts = append(parts, part{text: text[start:end], code: true})
+		text = text[end:]
+	}
+	if text != "" {
+		parts = append(parts, part{text: text})
+	}
+	return
+}
+
+// codes are the ids of the inline codes of a unit, shared by its source and
+// target.
+type codes struct {
+	ids  map[string]string
+	list []string
+}
+
+func (c *codes) id(code string) string {
+	if c.ids == nil {
+		c.ids = map[string]string{}
+	}
+	id, ok := c.ids[code]
+	if !ok {
+		c.list = append(c.list, code)
+		id = strconv.Itoa(len(c.list))
+		c.ids[code] = id
+	}
+	return id
+}
+
+// writer writes the XLIFF elements. The indentation is written by open, close
+// and the leaf elements, because the indentation of xml.Encoder changes the
+// mixed content of the source and target.
+type writer struct {
+	enc     *xml.Encoder
+	version string
+	depth   int
+	err     error
+}
+
+func (w *writer) indent() {
+	w.token(xml.CharData("\n" + strings.Repeat("  ", w.depth)))
+}
+
+// open starts a block element.
+func (w *writer) open(name string, attrs ...string) {
+	w.indent()
+	w.start(name, attrs...)
+	w.depth++
+}
+
+// close ends a block element.
+func (w *writer) close(name string) {
+	w.depth--
+	w.indent()
+	w.end(name)
+}
+
+func (w *writer) start(name string, attrs ...string) {
+	e := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i < len(attrs); i += 2 {
+		if attrs[i+1] != "" {
+			e.Attr = append(e.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+		}
+	}
+	w.token(e)
+}
+
+func (w *writer) end(name string) {
+	w.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (w *writer) token(t xml.Token) {
+	if w.err == nil {
+		w.err = w.enc.EncodeToken(t)
+	}
+}
+
+// element writes a leaf element.
+func (w *writer) element(name, text string, attrs ...string) {
+	w.indent()
+	w.start(name, attrs...)
+	w.token(xml.CharData(text))
+	w.end(name)
+}
+
+// content writes the element of the text, with the inline codes.
+func (w *writer) content(name, text string, template bool, c *codes, attrs ...string) {
+	w.indent()
+	w.start(name, attrs...)
+	for _, p := range split(text, template) {
+		if !p.code {
+			w.token(xml.CharData(p.text))
+			continue
+		}
+		id := c.id(p.text)
+		if w.version == Version12 {
+			w.start("ph", "id", id)
+			w.token(xml.CharData(p.text))
+			w.end("ph")
+		} else {
+			w.start("ph", "id", id, "dataRef", "d"+id)
+			w.end("ph")
+		}
+	}
+	w.end(name)
+}
+
+// Write writes the document as XLIFF of its Version.
+func (d *Document) Write(out io.Writer) error {
+	w := &writer{enc: xml.NewEncoder(out), version: d.Version}
+	if w.version == "" {
+		w.version = Version12
+	}
+	if w.version != Version12 && w.version != Version20 {
+		return fmt.Errorf("Invalid XLIFF version %q", w.version)
+	}
+	if _, err := io.WriteString(out, strings.TrimSuffix(xml.Header, "\n")); err != nil {
+		return err
+	}
+	if w.version == Version12 {
+		w.open("xliff", "version", Version12, "xmlns", "urn:oasis:names:tc:xliff:document:1.2")
+	} else {
+		w.open("xliff", "xmlns", "urn:oasis:names:tc:xliff:document:2.0", "version", Version20,
+			"srcLang", d.SourceLang, "trgLang", d.TargetLang)
+	}
+	for _, f := range d.Files {
+		if w.version == Version12 {
+			w.open("file", "original", f.Group, "source-language", d.SourceLang,
+				"target-language", d.TargetLang, "datatype", "plaintext")
+			w.open("body")
+		} else {
+			w.open("file", "id", f.Group)
+		}
+		for _, u := range f.Units {
+			if len(u.Variants) == 0 {
+				w.unit(u.Key, u.Kind, u.Notes, u.Source, u.Target, u.State, u.Template)
+				continue
+			}
+			if w.version == Version12 {
+				w.open("group", "id", u.Key, "restype", "x-gettext-plurals")
+			} else {
+				w.open("group", "id", u.Key, "type", "x-i18nmod:plural")
+			}
+			w.notes(u.Notes)
+			for _, v := range u.Variants {
+				w.unit(v.id(u.Key), KindText, nil, v.Source, v.Target, v.State, v.Template)
+			}
+			w.close("group")
+		}
+		if w.version == Version12 {
+			w.close("body")
+		}
+		w.close("file")
+	}
+	w.close("xliff")
+
+	if w.err == nil {
+		w.err = w.enc.Flush()
+	}
+	if w.err == nil {
+		_, w.err = io.WriteString(out, "\n")
+	}
+	return w.err
+}
+
+func (w *writer) notes(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+	if w.version == Version12 {
+		for _, n := range notes {
+			w.element("note", n)
+		}
+		return
+	}
+	w.open("notes")
+	for _, n := range notes {
+		w.element("note", n)
+	}
+	w.close("notes")
+}
+
+// kindTypes are the 1.2 restype and 2.0 type of the unit kinds.
+var kindTypes = map[string][2]string{
+	KindMessage: {"x-icu-message", "x-i18nmod:message"},
+}
+
+func (w *writer) unit(id, kind string, notes []string, source, target, state string, template bool) {
+	c := &codes{}
+	types := kindTypes[kind]
+	if w.version == Version12 {
+		w.open("trans-unit", "id", id, "restype", types[0])
+		w.content("source", source, template, c)
+		if target != "" || state != StateInitial {
+			w.content("target", target, template, c, "state", state12(state))
+		}
+		w.notes(notes)
+		w.close("trans-unit")
+		return
+	}
+
+	// the codes of the originalData are found before writing the segment
+	w.open("unit", "id", id, "type", types[1])
+	w.notes(notes)
+	for _, text := range []string{source, target} {
+		for _, p := range split(text, template) {
+			if p.code {
+				c.id(p.text)
+			}
+		}
+	}
+	if len(c.list) > 0 {
+		w.open("originalData")
+		for i, code := range c.list {
+			w.element("data", code, "id", "d"+strconv.Itoa(i+1))
+		}
+		w.close("originalData")
+	}
+	w.open("segment", "state", state)
+	w.content("source", source, template, c)
+	if target != "" {
+		w.content("target", target, template, c)
+	}
+	w.close("segment")
+	w.close("unit")
+}
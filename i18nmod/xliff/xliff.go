@@ -0,0 +1,373 @@
+// Package xliff exports the translations of a source and target locale pair to
+// XLIFF 1.2 or 2.0, and imports the translated files back as trees.
+//
+// Each group is a file, and each key is a unit. The plurals are groups of
+// units, one by case, with the "key[case]" ids, and the ordinal cases with the
+// "key[ordinal:case]" ids. The template placeholders ("{{...}}") are written as
+// inline codes, so the translators do not change them. The ICU messages are
+// units of the "x-icu-message" restype (1.2) or "x-i18nmod:message" type (2.0),
+// parsed again on import.
+//
+// The aliases and selects are not exported.
+package xliff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+)
+
+const (
+	Version12 = "1.2"
+	Version20 = "2.0"
+)
+
+// The XLIFF 2.0 states. The 1.2 states are converted to them on read.
+const (
+	StateInitial    = "initial"
+	StateTranslated = "translated"
+	StateReviewed   = "reviewed"
+	StateFinal      = "final"
+)
+
+// Document is a XLIFF document.
+type Document struct {
+	// Version is Version12 or Version20. The empty version writes 1.2.
+	Version    string
+	SourceLang string
+	TargetLang string
+	Files      []*File
+}
+
+// File is the XLIFF file of a group.
+type File struct {
+	Group string
+	Units []*Unit
+}
+
+// The kinds of the units without Variants.
+const (
+	// KindText is a text or template.
+	KindText = ""
+	// KindMessage is an ICU message.
+	KindMessage = "message"
+)
+
+// Unit is the translation of a key. The plurals have the Variants and not the
+// Source and Target.
+type Unit struct {
+	Key string
+	// Kind is KindText or KindMessage.
+	Kind   string
+	Notes  []string
+	Source string
+	Target string
+	// State is the XLIFF 2.0 state of the target.
+	State string
+	// Template marks the Source and Target as templates, with the
+	// placeholders written as inline codes.
+	Template bool
+	Variants []*Variant
+}
+
+// Variant is a plural or ordinal case of the unit.
+type Variant struct {
+	Case     string
+	Ordinal  bool
+	Source   string
+	Target   string
+	State    string
+	Template bool
+}
+
+// state20 returns the XLIFF 2.0 state of the 1.2 state.
+func state20(state string) string {
+	switch state {
+	case "", "new", "needs-translation", "needs-adaptation", "needs-l10n":
+		return StateInitial
+	case "signed-off":
+		return StateReviewed
+	case "final":
+		return StateFinal
+	}
+	return StateTranslated
+}
+
+// state12 returns the XLIFF 1.2 state of the 2.0 state.
+func state12(state string) string {
+	switch state {
+	case StateInitial:
+		return "needs-translation"
+	case StateReviewed:
+		return "signed-off"
+	}
+	return state
+}
+
+// NewDocument returns the document of the loaded translations of the source
+// locale, with their target locale translations. The aliases and selects are
+// not exported.
+func NewDocument(tr *i18nmod.Translator, source, target string) *Document {
+	d := &Document{Version: Version12, SourceLang: source, TargetLang: target}
+
+	tr.RLock()
+	defer tr.RUnlock()
+
+	for group, locales := range tr.Groups {
+		f := &File{Group: group}
+		for key, st := range locales[source] {
+			if st.Alias != "" || st.Select != nil {
+				continue
+			}
+			u := &Unit{Key: key}
+			tt := locales[target][key]
+			if st.Plural != nil || st.Ordinal != nil {
+				var tp, to *i18nmod.Plural
+				if tt != nil {
+					tp, to = tt.Plural, tt.Ordinal
+				}
+				u.Variants = append(variants(st.Plural, tp, false), variants(st.Ordinal, to, true)...)
+			} else {
+				if st.Message != nil {
+					u.Kind = KindMessage
+				}
+				u.Source, u.Template = text(st)
+				if tt != nil && tt.Plural == nil && tt.Ordinal == nil {
+					u.Target, _ = text(tt)
+				}
+				u.State = StateInitial
+				if u.Target != "" {
+					u.State = StateTranslated
+				}
+			}
+			f.Units = append(f.Units, u)
+		}
+		if len(f.Units) > 0 {
+			sort.Slice(f.Units, func(i, j int) bool {
+				return f.Units[i].Key < f.Units[j].Key
+			})
+			d.Files = append(d.Files, f)
+		}
+	}
+	sort.Slice(d.Files, func(i, j int) bool {
+		return d.Files[i].Group < d.Files[j].Group
+	})
+	return d
+}
+
+// text returns the text of t and if it is a template.
+func text(t *i18nmod.Translation) (string, bool) {
+	switch {
+	case t.Template != nil:
+		return t.Template.Text, true
+	case t.ValueTemplate != nil:
+		return t.Value, true
+	case t.Message != nil:
+		return t.Message.Pattern, false
+	}
+	return t.Value, false
+}
+
+func caseText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case *i18nmod.LazyTemplate:
+		return v.Text, true
+	case string:
+		return v, false
+	}
+	return "", false
+}
+
+// caseOrder is the order of the variants: the CLDR categories and then the
+// exact and expression cases.
+var caseOrder = map[string]int{"zero": 1, "one": 2, "two": 3, "few": 4, "many": 5, "other": 6}
+
+func pluralCases(p *i18nmod.Plural) map[string]interface{} {
+	cases := map[string]interface{}{}
+	if p == nil {
+		return cases
+	}
+	for k, v := range p.Cases {
+		switch k {
+		case "p", "s":
+			// aliases of "other" and "one"
+			continue
+		}
+		switch k := k.(type) {
+		case int:
+			cases[strconv.Itoa(k)] = v
+		default:
+			cases[fmt.Sprint(k)] = v
+		}
+	}
+	for k, v := range p.ExpCases {
+		cases[string(k.Cond)+k.Format+k.Value] = v
+	}
+	return cases
+}
+
+func variants(source, target *i18nmod.Plural, ordinal bool) (variants []*Variant) {
+	if source == nil {
+		return
+	}
+	sc, tc := pluralCases(source), pluralCases(target)
+	names := map[string]bool{}
+	for k := range sc {
+		names[k] = true
+	}
+	for k := range tc {
+		names[k] = true
+	}
+
+	for name := range names {
+		v := &Variant{Case: name, Ordinal: ordinal, State: StateInitial}
+		s, ok := sc[name]
+		if !ok {
+			s = sc["other"]
+		}
+		v.Source, v.Template = caseText(s)
+		if t, ok := tc[name]; ok {
+			var template bool
+			v.Target, template = caseText(t)
+			v.Template = v.Template || template
+		}
+		if v.Target != "" {
+			v.State = StateTranslated
+		}
+		if v.Source != "" || v.Target != "" {
+			variants = append(variants, v)
+		}
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		oi, oj := caseOrder[variants[i].Case], caseOrder[variants[j].Case]
+		if oi == 0 {
+			oi = len(caseOrder) + 1
+		}
+		if oj == 0 {
+			oj = len(caseOrder) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return variants[i].Case < variants[j].Case
+	})
+	return
+}
+
+// Merge copies the notes of the units of prev, and their states if the targets
+// are not changed.
+func (d *Document) Merge(prev *Document) {
+	units := map[string]*Unit{}
+	for _, f := range prev.Files {
+		for _, u := range f.Units {
+			units[f.Group+"."+u.Key] = u
+		}
+	}
+	for _, f := range d.Files {
+		for _, u := range f.Units {
+			pu, ok := units[f.Group+"."+u.Key]
+			if !ok {
+				continue
+			}
+			u.Notes = pu.Notes
+			if pu.Target == u.Target && pu.State != "" {
+				u.State = pu.State
+			}
+			for _, v := range u.Variants {
+				for _, pv := range pu.Variants {
+					if pv.Case == v.Case && pv.Ordinal == v.Ordinal && pv.Target == v.Target && pv.State != "" {
+						v.State = pv.State
+					}
+				}
+			}
+		}
+	}
+}
+
+// Trees returns the trees of the target translations, by group. The units
+// without target or of initial state are skipped.
+func (d *Document) Trees() (trees map[string]*i18nmod.Tree, err error) {
+	trees = map[string]*i18nmod.Tree{}
+	for _, f := range d.Files {
+		tree := &i18nmod.Tree{}
+		var n int
+		for _, u := range f.Units {
+			t := &i18nmod.Translation{Key: u.Key}
+			if len(u.Variants) > 0 {
+				plural, ordinal := &i18nmod.Plural{}, &i18nmod.Plural{Ordinal: true}
+				for _, v := range u.Variants {
+					if v.Target == "" || v.State == StateInitial {
+						continue
+					}
+					value, err := targetValue(v.Target, v.Template)
+					if err != nil {
+						return nil, fmt.Errorf("Parse translation [%v.%v%v] template failed: %v", f.Group, u.Key, v.id(""), err)
+					}
+					p := plural
+					if v.Ordinal {
+						p = ordinal
+					}
+					if i, err := strconv.Atoi(v.Case); err == nil {
+						p.AddCase(i, value)
+					} else {
+						p.AddCase(v.Case, value)
+					}
+				}
+				if plural.Cases != nil || plural.ExpCases != nil {
+					t.Plural = plural
+				}
+				if ordinal.Cases != nil || ordinal.ExpCases != nil {
+					t.Ordinal = ordinal
+				}
+				if t.Plural == nil && t.Ordinal == nil {
+					continue
+				}
+			} else {
+				if u.Target == "" || u.State == StateInitial {
+					continue
+				}
+				t.Value = u.Target
+				if u.Kind == KindMessage {
+					if t.Message, err = i18nmod.ParseMessage(u.Target); err != nil {
+						return nil, fmt.Errorf("Parse translation [%v.%v] message failed: %v", f.Group, u.Key, err)
+					}
+				} else {
+					value, err := targetValue(u.Target, u.Template)
+					if err != nil {
+						return nil, fmt.Errorf("Parse translation [%v.%v] template failed: %v", f.Group, u.Key, err)
+					}
+					if tpl, ok := value.(*i18nmod.LazyTemplate); ok {
+						t.Template = tpl
+					}
+				}
+			}
+			tree.Add(t)
+			n++
+		}
+		if n > 0 {
+			trees[f.Group] = tree
+		}
+	}
+	return
+}
+
+// id returns the unit id of the variant of the unit key.
+func (v *Variant) id(key string) string {
+	if v.Ordinal {
+		return key + "[ordinal:" + v.Case + "]"
+	}
+	return key + "[" + v.Case + "]"
+}
+
+func targetValue(target string, template bool) (interface{}, error) {
+	if template || strings.Contains(target, "{{") {
+		return i18nmod.ParseLazyTemplate(target)
+	}
+	return target, nil
+}
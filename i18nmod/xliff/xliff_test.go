@@ -0,0 +1,162 @@
+package xliff_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/i18n-modular/i18nmod"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/backends/yaml"
+	"github.com/moisespsena-go/i18n-modular/i18nmod/xliff"
+)
+
+func translator(t *testing.T) *i18nmod.Translator {
+	backend := yaml.New()
+	backend.AddInput("messages", "en", func() ([]byte, error) {
+		return []byte(`
+hello: Hello
+greet~: "Hi {{.Name}}!"
+files*:
+  one: one file
+  other~: "{{count}} files"
+title@: .hello
+count!: "{count, plural, one {# file} other {# files}}"
+place#:
+  one~: "{{count}}st"
+  other~: "{{count}}th"
+`), nil
+	})
+	backend.AddInput("messages", "pt-BR", func() ([]byte, error) {
+		return []byte(`
+greet~: "Oi {{.Name}}!"
+files*:
+  one: um arquivo
+count!: "{count, plural, one {# arquivo} other {# arquivos}}"
+place#:
+  other~: "{{count}}º"
+`), nil
+	})
+	tr := i18nmod.NewTranslator()
+	tr.AddBackend(backend)
+	if err := tr.PreloadAll(); err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, version := range []string{xliff.Version12, xliff.Version20} {
+		doc := xliff.NewDocument(translator(t), "en", "pt-BR")
+		doc.Version = version
+
+		var b bytes.Buffer
+		if err := doc.Write(&b); err != nil {
+			t.Fatal(err)
+		}
+		out := b.String()
+		for _, expected := range map[string][]string{
+			xliff.Version12: {
+				`<file original="messages" source-language="en" target-language="pt-BR" datatype="plaintext">`,
+				`<source>Hi <ph id="1">{{.Name}}</ph>!</source>`,
+				`<group id="files" restype="x-gettext-plurals">`,
+				`<trans-unit id="files[other]">`,
+				`<trans-unit id="count" restype="x-icu-message">`,
+				`<source>{count, plural, one {# file} other {# files}}</source>`,
+				`<trans-unit id="place[ordinal:one]">`,
+			},
+			xliff.Version20: {
+				`srcLang="en" trgLang="pt-BR"`,
+				`<data id="d1">{{.Name}}</data>`,
+				`<target>Oi <ph id="1" dataRef="d1"></ph>!</target>`,
+				`<segment state="initial">`,
+				`<unit id="count" type="x-i18nmod:message">`,
+				`<unit id="place[ordinal:other]">`,
+			},
+		}[version] {
+			if !strings.Contains(out, expected) {
+				t.Errorf("%s: expected %s in:\n%s", version, expected, out)
+			}
+		}
+		if strings.Contains(out, "title") {
+			t.Errorf("%s: alias exported", version)
+		}
+
+		// the vendor translates the units
+		if version == xliff.Version12 {
+			out = strings.Replace(out, `<source>Hello</source>`, `<source>Hello</source><target state="translated">Olá</target><note>greeting</note>`, 1)
+		} else {
+			out = strings.Replace(out, `<unit id="hello">`, `<unit id="hello"><notes><note>greeting</note></notes>`, 1)
+			out = strings.Replace(out, `<segment state="initial">
+        <source>Hello</source>`, `<segment state="final"><source>Hello</source><target>Olá</target>`, 1)
+		}
+
+		read, err := xliff.Read(strings.NewReader(out))
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", version, err, out)
+		}
+		if read.Version != version || read.SourceLang != "en" || read.TargetLang != "pt-BR" {
+			t.Errorf("%s: invalid document %v", version, read)
+		}
+		// the variants of empty or malformed cases are rejected
+		if !strings.Contains(out, `id="files[one]"`) {
+			t.Fatalf("%s: files[one] variant not exported\n%s", version, out)
+		}
+		for _, id := range []string{"files[]", "files[=]", "files[>%d]", "files[ordinal:]"} {
+			if _, err := xliff.Read(strings.NewReader(strings.Replace(out, `id="files[one]"`, `id="`+id+`"`, 1))); err == nil {
+				t.Errorf("%s: expected error of %s", version, id)
+			}
+		}
+
+		units := map[string]*xliff.Unit{}
+		for _, u := range read.Files[0].Units {
+			units[u.Key] = u
+		}
+		if u := units["hello"]; u == nil || u.Target != "Olá" || len(u.Notes) != 1 || u.Notes[0] != "greeting" {
+			t.Errorf("%s: invalid hello unit %+v", version, u)
+		}
+		if u := units["files"]; u == nil || len(u.Variants) != 2 || u.Variants[0].Case != "one" || u.Variants[1].State != xliff.StateInitial {
+			t.Errorf("%s: invalid files unit %+v", version, u)
+		}
+		if u := units["count"]; u == nil || u.Kind != xliff.KindMessage {
+			t.Errorf("%s: invalid count unit %+v", version, u)
+		}
+		if u := units["place"]; u == nil || len(u.Variants) != 2 || !u.Variants[0].Ordinal || u.Variants[1].Case != "other" {
+			t.Errorf("%s: invalid place unit %+v", version, u)
+		}
+
+		trees, err := read.Trees()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree := trees["messages"]
+		if tn := tree.Children["hello"].T; tn.Value != "Olá" {
+			t.Errorf("%s: expected %q, got %q", version, "Olá", tn.Value)
+		}
+		if tn := tree.Children["greet"].T; tn.Template == nil || tn.Template.Text != "Oi {{.Name}}!" {
+			t.Errorf("%s: invalid greet translation %+v", version, tn)
+		}
+		if p := tree.Children["files"].T.Plural; p == nil || p.Cases["one"] != "um arquivo" || p.Cases["other"] != nil {
+			t.Errorf("%s: invalid files plural %+v", version, p)
+		}
+
+		// the imported messages and ordinals are translated
+		imported := i18nmod.NewTranslator()
+		imported.SetGroup("pt-BR", "messages", tree)
+		ctx := imported.NewContext("pt-BR")
+		if got := ctx.T("messages.count").Count(2).Get(); got != "2 arquivos" {
+			t.Errorf("%s: expected %q, got %q", version, "2 arquivos", got)
+		}
+		if got := ctx.T("messages.place").Ordinal(3).Get(); got != "3º" {
+			t.Errorf("%s: expected %q, got %q", version, "3º", got)
+		}
+
+		// the notes and states are kept by the next export
+		next := xliff.NewDocument(translator(t), "en", "pt-BR")
+		next.Merge(read)
+		for _, u := range next.Files[0].Units {
+			if u.Key == "hello" && (len(u.Notes) != 1 || u.State != xliff.StateInitial) {
+				t.Errorf("%s: notes not merged %+v", version, u)
+			}
+		}
+	}
+}